@@ -2,4 +2,7 @@ module l7e.io/semver
 
 go 1.14
 
-require github.com/smartystreets/goconvey v1.6.4
+require (
+	github.com/smartystreets/goconvey v1.6.4
+	gopkg.in/yaml.v2 v2.4.0
+)
@@ -17,6 +17,7 @@
 package semver_test
 
 import (
+	"strings"
 	"testing"
 
 	"l7e.io/semver/v1"
@@ -77,35 +78,157 @@ func BenchmarkRangeParseComplex(b *testing.B) {
 	}
 }
 
-func BenchmarkRangeMatchSimple(b *testing.B) {
-	const VERSION = ">1.0.0"
-	r, _ := semver.ParseRange(VERSION)
-	v := semver.New("2.0.0")
+func BenchmarkRangeParseExclusions(b *testing.B) {
+	VERSION := exclusions(20)
 	b.ReportAllocs()
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		r(v)
+		_, _ = semver.ParseRange(VERSION)
+	}
+}
+
+// closures returns a Range for a range of complete versions built the way
+// ParseRange did before ranges were compiled to intervals: a closure for each
+// comparator, combined with AND and OR.  It is the baseline of the
+// BenchmarkRangeMatch benchmarks.
+func closures(s string) semver.Range {
+	var or semver.Range
+	for _, group := range strings.Split(s, "||") {
+		var and semver.Range
+		for _, term := range strings.Fields(group) {
+			op := strings.TrimRight(term, "0123456789.")
+			bound := semver.New(term[len(op):])
+			var rf semver.Range
+			switch op {
+			case ">":
+				rf = func(v *semver.Version) bool { return v.Compare(bound) > 0 }
+			case ">=":
+				rf = func(v *semver.Version) bool { return v.Compare(bound) >= 0 }
+			case "<":
+				rf = func(v *semver.Version) bool { return v.Compare(bound) < 0 }
+			case "<=":
+				rf = func(v *semver.Version) bool { return v.Compare(bound) <= 0 }
+			case "!=":
+				rf = func(v *semver.Version) bool { return v.Compare(bound) != 0 }
+			default:
+				rf = func(v *semver.Version) bool { return v.Compare(bound) == 0 }
+			}
+			if and == nil {
+				and = rf
+			} else {
+				and = and.AND(rf)
+			}
+		}
+		if or == nil {
+			or = and
+		} else {
+			or = or.OR(and)
+		}
+	}
+	return or
+}
+
+// benchmarkRangeMatch compares matching versions against the closures of a
+// range with matching them against its compiled intervals.
+func benchmarkRangeMatch(b *testing.B, s string, versions ...*semver.Version) {
+	for _, bc := range []struct {
+		name string
+		r    semver.Range
+	}{
+		{"closures", closures(s)},
+		{"intervals", semver.MustParseRange(s)},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				for _, v := range versions {
+					bc.r(v)
+				}
+			}
+		})
 	}
 }
 
+func BenchmarkRangeMatchSimple(b *testing.B) {
+	benchmarkRangeMatch(b, ">1.0.0", semver.New("2.0.0"))
+}
+
 func BenchmarkRangeMatchAverage(b *testing.B) {
-	const VERSION = ">=1.0.0 <2.0.0"
-	r, _ := semver.ParseRange(VERSION)
-	v := semver.New("1.2.3")
+	benchmarkRangeMatch(b, ">=1.0.0 <2.0.0", semver.New("1.2.3"))
+}
+
+func BenchmarkRangeMatchComplex(b *testing.B) {
+	benchmarkRangeMatch(b, ">=1.0.0 <2.0.0 || >=3.0.1 <4.0.0 !=3.0.3 || >=5.0.0", semver.New("5.0.1"))
+}
+
+// catalog returns n sorted releases, ten patch versions for each of ten
+// minor versions of every major version.
+func catalog(n int) semver.Versions {
+	versions := make(semver.Versions, n)
+	for i := range versions {
+		versions[i] = &semver.Version{Major: uint64(i / 100), Minor: uint64(i / 10 % 10), Patch: uint64(i % 10)}
+	}
+	return versions
+}
+
+func BenchmarkRangeMatchCatalog(b *testing.B) {
+	benchmarkRangeMatch(b, ">=1.0.0 <2.0.0 || >=3.0.1 <4.0.0 !=3.0.3 || >=500.0.0", catalog(100000)...)
+}
+
+func BenchmarkFilterVersions(b *testing.B) {
+	const VERSION = ">=1.0.0 <2.0.0 || >=3.0.1 <4.0.0 !=3.0.3 || >=500.0.0"
+	c, _ := semver.ParseConstraint(VERSION)
+	versions := catalog(100000)
 	b.ReportAllocs()
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		r(v)
+		c.FilterVersions(versions)
 	}
 }
 
-func BenchmarkRangeMatchComplex(b *testing.B) {
-	const VERSION = ">=1.0.0 <2.0.0 || >=3.0.1 <4.0.0 !=3.0.3 || >=5.0.0"
-	r, _ := semver.ParseRange(VERSION)
-	v := semver.New("5.0.1")
+func registry(n int) *semver.Registry {
+	r := semver.NewRegistry()
+	for _, v := range catalog(n) {
+		r.Add("api", v)
+	}
+	return r
+}
+
+func BenchmarkRegistryLatestParallel(b *testing.B) {
+	r := registry(10000)
+	rf := semver.MustParseRange("98.x")
 	b.ReportAllocs()
 	b.ResetTimer()
-	for n := 0; n < b.N; n++ {
-		r(v)
-	}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r.Latest("api", rf, semver.StabilityStable)
+		}
+	})
+}
+
+func BenchmarkRegistryLatestParallelWithWriter(b *testing.B) {
+	r := registry(10000)
+	rf := semver.MustParseRange("98.x")
+	v := semver.New("98.9.10")
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				r.Add("api", v)
+				r.Remove("api", v)
+			}
+		}
+	}()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r.Latest("api", rf, semver.StabilityStable)
+		}
+	})
+	b.StopTimer()
+	close(done)
 }
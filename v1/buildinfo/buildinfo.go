@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+/*
+Package buildinfo exposes the version of the running program.
+
+The version is taken, in order of preference, from the Version variable,
+which can be set at link time:
+
+	go build -ldflags "-X l7e.io/semver/v1/buildinfo.Version=1.2.3"
+
+from the version of the main module recorded by the Go toolchain, or from a
+pseudo-version made of the commit time and revision recorded by the VCS
+settings of the build.  The commit and whether the working tree was modified
+are added as build metadata, such as "1.2.3+3f2e1d0c9b8a.dirty", after any
+metadata of the module version, such as "2.0.0+incompatible.3f2e1d0c9b8a".
+*/
+package buildinfo // import "l7e.io/semver/v1/buildinfo"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"l7e.io/semver/v1"
+)
+
+// Version overrides the version of the program when it is set at link time.
+var Version string
+
+// The sources of a version.
+const (
+	SourceLDFlags = "ldflags"
+	SourceModule  = "module"
+	SourceVCS     = "vcs"
+	SourceNone    = "none"
+)
+
+// revisionLength is the length of the commit in the build metadata, as
+// used by Go pseudo-versions.
+const revisionLength = 12
+
+// Info describes how the running program was built.
+type Info struct {
+	Version   *semver.Version `json:"version"`
+	Source    string          `json:"source"`
+	Module    string          `json:"module,omitempty"`
+	GoVersion string          `json:"goVersion,omitempty"`
+	Revision  string          `json:"revision,omitempty"`
+	Time      *time.Time      `json:"time,omitempty"`
+	Modified  bool            `json:"modified"`
+}
+
+// Read returns the Info of the running program.  An error is returned if
+// the program was built without module support or if Version is not a
+// valid semantic version.
+func Read() (*Info, error) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil, errors.New("build information is not available")
+	}
+	return FromBuildInfo(bi, Version)
+}
+
+// FromBuildInfo returns the Info of a program built as described by bi.
+// If override is not empty it is used as the version, with or without a
+// leading "v".
+func FromBuildInfo(bi *debug.BuildInfo, override string) (*Info, error) {
+	goVersion, settings := settings(bi)
+	info := &Info{
+		Source:    SourceNone,
+		Module:    bi.Main.Path,
+		GoVersion: goVersion,
+		Revision:  settings["vcs.revision"],
+		Modified:  settings["vcs.modified"] == "true",
+	}
+	if t, err := time.Parse(time.RFC3339Nano, settings["vcs.time"]); err == nil {
+		info.Time = &t
+	}
+
+	var s string
+	switch {
+	case len(override) > 0:
+		s, info.Source = override, SourceLDFlags
+	case len(bi.Main.Version) > 0 && bi.Main.Version != "(devel)":
+		s, info.Source = bi.Main.Version, SourceModule
+	case len(info.Revision) > 0 && info.Time != nil:
+		s, info.Source = pseudoVersion(*info.Time, info.Revision), SourceVCS
+	default:
+		s = "0.0.0-devel"
+	}
+
+	v, err := semver.NewVersion(strings.TrimPrefix(s, "v"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s version: %s", info.Source, err)
+	}
+	if len(info.Revision) > 0 {
+		switch {
+		case info.Source == SourceModule:
+			v.Metadata = append(moduleMetadata(v.Metadata), info.metadata()...)
+		case len(v.Metadata) == 0:
+			v.Metadata = info.metadata()
+		}
+	}
+	info.Version = v
+
+	return info, nil
+}
+
+// pseudoVersion returns a Go pseudo-version for a commit without a tag.
+func pseudoVersion(t time.Time, revision string) string {
+	return "0.0.0-" + t.UTC().Format("20060102150405") + "-" + shorten(revision)
+}
+
+func shorten(revision string) string {
+	if len(revision) > revisionLength {
+		return revision[:revisionLength]
+	}
+	return revision
+}
+
+// moduleMetadata returns the build metadata of a module version without the
+// "dirty" identifier that the toolchain adds for a modified working tree, as
+// metadata adds it after the commit.  Other identifiers, such as the
+// "incompatible" of a v2+ module without a go.mod, are kept.
+func moduleMetadata(ids semver.Identifiers) semver.Identifiers {
+	kept := semver.Identifiers{}
+	for _, id := range ids {
+		if id.IsNum || id.Str != "dirty" {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+// metadata returns the build metadata of the commit and modification flag.
+func (i *Info) metadata() semver.Identifiers {
+	ids := semver.Identifiers{{Str: shorten(i.Revision)}}
+	if i.Modified {
+		ids = append(ids, semver.Identifier{Str: "dirty"})
+	}
+	return ids
+}
+
+// String returns the version of the program.
+func (i *Info) String() string {
+	return i.Version.String()
+}
+
+// Handler returns an http.Handler for "/version" endpoints that responds
+// with the Info of the running program as JSON, or with its version as
+// plain text if text/plain is the first media type the request accepts.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := Read()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		info.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP responds with i as JSON, or with its version as plain text if
+// text/plain is the first media type the request accepts.
+func (i *Info) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if accept := r.Header.Get("Accept"); strings.HasPrefix(accept, "text/plain") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = fmt.Fprintln(w, i.String())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(i)
+}
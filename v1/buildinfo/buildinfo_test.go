@@ -0,0 +1,146 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildinfo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1/buildinfo"
+)
+
+func build(version string, settings map[string]string) *debug.BuildInfo {
+	bi := &debug.BuildInfo{GoVersion: "go1.22.1", Main: debug.Module{Path: "example.com/app", Version: version}}
+	for k, v := range settings {
+		bi.Settings = append(bi.Settings, debug.BuildSetting{Key: k, Value: v})
+	}
+	return bi
+}
+
+func TestFromBuildInfo(t *testing.T) {
+	vcs := map[string]string{
+		"vcs":          "git",
+		"vcs.revision": "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e",
+		"vcs.time":     "2024-10-16T12:34:56Z",
+		"vcs.modified": "false",
+	}
+	dirty := map[string]string{
+		"vcs.revision": "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e",
+		"vcs.time":     "2024-10-16T12:34:56Z",
+		"vcs.modified": "true",
+	}
+
+	Convey("Test versions from build information", t, func() {
+		tests := []struct {
+			name      string
+			bi        *debug.BuildInfo
+			override  string
+			expected  string
+			source    string
+			revision  string
+			modified  bool
+			hasCommit bool
+		}{
+			{"ldflags", build("v1.0.0", vcs), "v2.3.4", "2.3.4+3f2e1d0c9b8a", buildinfo.SourceLDFlags, "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e", false, true},
+			{"ldflags with metadata", build("v1.0.0", vcs), "2.3.4+ci.42", "2.3.4+ci.42", buildinfo.SourceLDFlags, "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e", false, true},
+			{"module", build("v1.2.3", nil), "", "1.2.3", buildinfo.SourceModule, "", false, false},
+			{"module with vcs", build("v1.2.3+dirty", dirty), "", "1.2.3+3f2e1d0c9b8a.dirty", buildinfo.SourceModule, "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e", true, true},
+			{"incompatible module", build("v2.0.0+incompatible", nil), "", "2.0.0+incompatible", buildinfo.SourceModule, "", false, false},
+			{"incompatible module with vcs", build("v2.0.0+incompatible", vcs), "", "2.0.0+incompatible.3f2e1d0c9b8a", buildinfo.SourceModule, "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e", false, true},
+			{"dirty incompatible module", build("v2.0.0+incompatible.dirty", dirty), "", "2.0.0+incompatible.3f2e1d0c9b8a.dirty", buildinfo.SourceModule, "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e", true, true},
+			{"pseudo-version", build("(devel)", vcs), "", "0.0.0-20241016123456-3f2e1d0c9b8a+3f2e1d0c9b8a", buildinfo.SourceVCS, "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e", false, true},
+			{"dirty pseudo-version", build("(devel)", dirty), "", "0.0.0-20241016123456-3f2e1d0c9b8a+3f2e1d0c9b8a.dirty", buildinfo.SourceVCS, "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e", true, true},
+			{"none", build("(devel)", nil), "", "0.0.0-devel", buildinfo.SourceNone, "", false, false},
+		}
+		for _, tc := range tests {
+			Convey(tc.name, func() {
+				info, err := buildinfo.FromBuildInfo(tc.bi, tc.override)
+				So(err, ShouldBeNil)
+				So(info.Version.String(), ShouldEqual, tc.expected)
+				So(info.String(), ShouldEqual, tc.expected)
+				So(info.Source, ShouldEqual, tc.source)
+				So(info.Module, ShouldEqual, "example.com/app")
+				So(info.GoVersion, ShouldEqual, "go1.22.1")
+				So(info.Revision, ShouldEqual, tc.revision)
+				So(info.Modified, ShouldEqual, tc.modified)
+				So(info.Time != nil, ShouldEqual, tc.hasCommit)
+			})
+		}
+	})
+
+	Convey("Test invalid versions", t, func() {
+		_, err := buildinfo.FromBuildInfo(build("v1.0.0", nil), "1.0")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestRead(t *testing.T) {
+	Convey("Test reading the build information of the test binary", t, func() {
+		info, err := buildinfo.Read()
+		So(err, ShouldBeNil)
+		So(info.Version, ShouldNotBeNil)
+
+		buildinfo.Version = "9.8.7"
+		defer func() { buildinfo.Version = "" }()
+		info, err = buildinfo.Read()
+		So(err, ShouldBeNil)
+		So(info.Source, ShouldEqual, buildinfo.SourceLDFlags)
+		So(info.Version.Major, ShouldEqual, 9)
+	})
+}
+
+func TestHandler(t *testing.T) {
+	Convey("Test serving build information", t, func() {
+		info, err := buildinfo.FromBuildInfo(build("(devel)", map[string]string{
+			"vcs.revision": "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e",
+			"vcs.time":     "2024-10-16T12:34:56Z",
+			"vcs.modified": "true",
+		}), "1.2.3")
+		So(err, ShouldBeNil)
+
+		rec := httptest.NewRecorder()
+		info.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+		var body map[string]interface{}
+		So(json.Unmarshal(rec.Body.Bytes(), &body), ShouldBeNil)
+		So(body["version"], ShouldEqual, "1.2.3+3f2e1d0c9b8a.dirty")
+		So(body["source"], ShouldEqual, "ldflags")
+		So(body["revision"], ShouldEqual, "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e")
+		So(body["time"], ShouldEqual, "2024-10-16T12:34:56Z")
+		So(body["modified"], ShouldEqual, true)
+
+		req := httptest.NewRequest(http.MethodGet, "/version", nil)
+		req.Header.Set("Accept", "text/plain")
+		rec = httptest.NewRecorder()
+		info.ServeHTTP(rec, req)
+		So(rec.Body.String(), ShouldEqual, "1.2.3+3f2e1d0c9b8a.dirty\n")
+
+		rec = httptest.NewRecorder()
+		buildinfo.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(json.Unmarshal(rec.Body.Bytes(), &body), ShouldBeNil)
+		So(body["version"], ShouldNotBeEmpty)
+	})
+}
@@ -0,0 +1,31 @@
+//go:build !go1.18
+// +build !go1.18
+
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// settings returns the Go version of the running program and no build
+// settings, as they are only recorded since Go 1.18.
+func settings(*debug.BuildInfo) (string, map[string]string) {
+	return runtime.Version(), map[string]string{}
+}
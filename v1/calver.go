@@ -0,0 +1,443 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calverField identifies a field of a CalVer.  The order of the fields is
+// the order in which they are compared.
+type calverField int
+
+const (
+	calverYear calverField = iota
+	calverMonth
+	calverWeek
+	calverDay
+	calverMajor
+	calverMinor
+	calverMicro
+	calverModifier
+)
+
+type calverToken struct {
+	name    string
+	field   calverField
+	pattern string
+	// width is the zero padded width of the token, or 0 if it is not padded.
+	width int
+	// short is true if the token is a year since 2000.
+	short bool
+}
+
+// calverTokens are the tokens of https://calver.org, longest first so that
+// "YYYY" is preferred to "YY".
+var calverTokens = []calverToken{
+	{name: "MODIFIER", field: calverModifier, pattern: `[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*`},
+	{name: "MAJOR", field: calverMajor, pattern: `0|[1-9][0-9]*`},
+	{name: "MINOR", field: calverMinor, pattern: `0|[1-9][0-9]*`},
+	{name: "MICRO", field: calverMicro, pattern: `0|[1-9][0-9]*`},
+	{name: "YYYY", field: calverYear, pattern: `[0-9]{4}`},
+	{name: "YY", field: calverYear, pattern: `0|[1-9][0-9]*`, short: true},
+	{name: "0Y", field: calverYear, pattern: `[0-9]{2}|[1-9][0-9]{2,}`, width: 2, short: true},
+	{name: "MM", field: calverMonth, pattern: `1[0-2]|[1-9]`},
+	{name: "0M", field: calverMonth, pattern: `0[1-9]|1[0-2]`, width: 2},
+	{name: "WW", field: calverWeek, pattern: `5[0-3]|[1-4][0-9]|[1-9]`},
+	{name: "0W", field: calverWeek, pattern: `5[0-3]|[1-4][0-9]|0[1-9]`, width: 2},
+	{name: "DD", field: calverDay, pattern: `3[01]|[12][0-9]|[1-9]`},
+	{name: "0D", field: calverDay, pattern: `3[01]|[12][0-9]|0[1-9]`, width: 2},
+}
+
+const calverSeparators = ".-_"
+
+// CalVerFormat is a calendar versioning format template such as
+// "YYYY.0M.0D" or "YY.MM.MICRO", using the tokens of https://calver.org.
+type CalVerFormat struct {
+	template  string
+	tokens    []calverToken
+	seps      []string
+	pattern   *regexp.Regexp
+	hasFields [calverModifier + 1]bool
+}
+
+// NewCalVerFormat parses a format template.  Tokens are separated by one of
+// ".", "-" or "_" and each token may only be used once.  A year is required,
+// a day requires a month and a week cannot be combined with either.  A
+// MODIFIER must come last and is optional in versions, as in
+// "YYYY.0M.0D-MODIFIER".
+func NewCalVerFormat(template string) (*CalVerFormat, error) {
+	f := &CalVerFormat{template: template}
+	expr := "^"
+	sep := ""
+	for i := 0; i < len(template); {
+		if i > 0 {
+			if !strings.ContainsRune(calverSeparators, rune(template[i])) {
+				return nil, fmt.Errorf("expected separator at %d in format %q", i, template)
+			}
+			sep = template[i : i+1]
+			i++
+		}
+
+		var token *calverToken
+		for j := range calverTokens {
+			if strings.HasPrefix(template[i:], calverTokens[j].name) {
+				token = &calverTokens[j]
+				break
+			}
+		}
+		if token == nil {
+			return nil, fmt.Errorf("unknown token at %d in format %q", i, template)
+		}
+		if f.hasFields[token.field] {
+			return nil, fmt.Errorf("token %s is repeated in format %q", token.name, template)
+		}
+		if f.hasFields[calverModifier] {
+			return nil, fmt.Errorf("MODIFIER must be the last token in format %q", template)
+		}
+		f.hasFields[token.field] = true
+		f.tokens = append(f.tokens, *token)
+		f.seps = append(f.seps, sep)
+		i += len(token.name)
+
+		if token.field == calverModifier {
+			if len(sep) == 0 {
+				return nil, fmt.Errorf("MODIFIER cannot be the only token in format %q", template)
+			}
+			expr += "(?:" + regexp.QuoteMeta(sep) + "(" + token.pattern + "))?"
+		} else {
+			expr += regexp.QuoteMeta(sep) + "(" + token.pattern + ")"
+		}
+	}
+
+	if len(f.tokens) == 0 {
+		return nil, errors.New("format template empty")
+	}
+	if !f.hasFields[calverYear] {
+		return nil, fmt.Errorf("format %q has no year", template)
+	}
+	if f.hasFields[calverWeek] && (f.hasFields[calverMonth] || f.hasFields[calverDay]) {
+		return nil, fmt.Errorf("format %q combines a week with a month or day", template)
+	}
+	if f.hasFields[calverDay] && !f.hasFields[calverMonth] {
+		return nil, fmt.Errorf("format %q has a day without a month", template)
+	}
+	f.pattern = regexp.MustCompile(expr + "$")
+
+	return f, nil
+}
+
+// MustCalVerFormat is a helper for wrapping NewCalVerFormat and will panic if err is not nil.
+func MustCalVerFormat(f *CalVerFormat, err error) *CalVerFormat {
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Parse parses s to create an instance of CalVer in format f.
+// It will return an error if s does not match the template of f.
+func (f *CalVerFormat) Parse(s string) (*CalVer, error) {
+	m := f.pattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("version %q does not match format %q", s, f.template)
+	}
+
+	c := &CalVer{Format: f}
+	for i, token := range f.tokens {
+		str := m[i+1]
+		if token.field == calverModifier {
+			c.Modifier = str
+			continue
+		}
+		n, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		switch token.field {
+		case calverYear:
+			if n > math.MaxInt32 {
+				return nil, fmt.Errorf("year out of range in %q", s)
+			}
+			c.Year = int(n)
+			if token.short {
+				c.Year += 2000
+			}
+		case calverMonth:
+			c.Month = int(n)
+		case calverWeek:
+			c.Week = int(n)
+		case calverDay:
+			c.Day = int(n)
+		case calverMajor:
+			c.Major = n
+		case calverMinor:
+			c.Minor = n
+		case calverMicro:
+			c.Micro = n
+		}
+	}
+	if f.hasFields[calverDay] {
+		if d := time.Date(c.Year, time.Month(c.Month), c.Day, 0, 0, 0, 0, time.UTC); d.Day() != c.Day {
+			return nil, fmt.Errorf("invalid day in %q", s)
+		}
+	}
+
+	return c, nil
+}
+
+func (f *CalVerFormat) String() string {
+	return f.template
+}
+
+// CalVer represents a calendar version in a CalVerFormat.  Fields that are
+// not part of the format are zero.  The year is always the full year, even
+// for the short year tokens "YY" and "0Y".
+type CalVer struct {
+	Format   *CalVerFormat
+	Year     int
+	Month    int
+	Week     int
+	Day      int
+	Major    uint64
+	Minor    uint64
+	Micro    uint64
+	Modifier string
+}
+
+// NewCalVer parses s to create an instance of CalVer in the given format template.
+func NewCalVer(format, s string) (*CalVer, error) {
+	f, err := NewCalVerFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	return f.Parse(s)
+}
+
+// MustCalVer is a helper for wrapping NewCalVer and will panic if err is not nil.
+func MustCalVer(c *CalVer, err error) *CalVer {
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Compare tests if c is less than, equal to, or greater than o, returning
+// -1, 0, or +1 respectively.  The date is compared first, then the major,
+// minor and micro numbers.  A version with a modifier is less than the same
+// version without, and modifiers are compared as pre-release identifiers.
+func (c *CalVer) Compare(o *CalVer) int {
+	if r := c.compareDate(o); r != 0 {
+		return r
+	}
+	if r := compareUint(c.Major, o.Major); r != 0 {
+		return r
+	}
+	if r := compareUint(c.Minor, o.Minor); r != 0 {
+		return r
+	}
+	if r := compareUint(c.Micro, o.Micro); r != 0 {
+		return r
+	}
+	return c.modifier().Compare(o.modifier())
+}
+
+func (c *CalVer) compareDate(o *CalVer) int {
+	if r := compareInt(c.Year, o.Year); r != 0 {
+		return r
+	}
+	if r := compareInt(c.Month, o.Month); r != 0 {
+		return r
+	}
+	if r := compareInt(c.Week, o.Week); r != 0 {
+		return r
+	}
+	return compareInt(c.Day, o.Day)
+}
+
+func (c *CalVer) modifier() Identifiers {
+	ids := Identifiers{}
+	if len(c.Modifier) == 0 {
+		return ids
+	}
+	for _, str := range strings.Split(c.Modifier, ".") {
+		id, err := newIdentifier(str, false)
+		if err != nil {
+			id = Identifier{Str: str}
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Bump returns the version that follows c when released at now.  If the date
+// of now in the format of c is later than the date of c, the version takes
+// that date and its MAJOR, MINOR and MICRO are reset to zero.  If the date is
+// the same, the least significant of MICRO, MINOR and MAJOR is incremented
+// instead.  The modifier is always dropped.
+//
+// An error is returned if now is before the date of c, or if the date is the
+// same and the format has nothing else to increment.
+func (c *CalVer) Bump(now time.Time) (*CalVer, error) {
+	f := c.Format
+	next := &CalVer{Format: f, Major: c.Major, Minor: c.Minor, Micro: c.Micro}
+	next.Year = now.Year()
+	if f.hasFields[calverWeek] {
+		next.Year, next.Week = now.ISOWeek()
+	}
+	if f.hasFields[calverMonth] {
+		next.Month = int(now.Month())
+	}
+	if f.hasFields[calverDay] {
+		next.Day = now.Day()
+	}
+	for _, token := range f.tokens {
+		if token.short && next.Year < 2000 {
+			return nil, fmt.Errorf("year %d cannot be written in format %q", next.Year, f.template)
+		}
+	}
+
+	switch r := next.compareDate(c); {
+	case r < 0:
+		return nil, fmt.Errorf("%s is before the date of version %s", now.Format("2006-01-02"), c)
+	case r > 0:
+		next.Major, next.Minor, next.Micro = 0, 0, 0
+	case f.hasFields[calverMicro]:
+		next.Micro++
+	case f.hasFields[calverMinor]:
+		next.Minor++
+		next.Micro = 0
+	case f.hasFields[calverMajor]:
+		next.Major++
+		next.Minor, next.Micro = 0, 0
+	default:
+		return nil, fmt.Errorf("version %s was already released on %s", c, now.Format("2006-01-02"))
+	}
+
+	return next, nil
+}
+
+// packedWidths are the number of decimal digits given to each field when
+// more than three fields are packed into the patch number of a Version.
+var packedWidths = [calverModifier]uint64{calverWeek: 2, calverDay: 2, calverMajor: 6, calverMinor: 6, calverMicro: 6}
+
+// Version converts c into a Version that sorts in the same order as c among
+// versions of the same format.  The fields of the format are taken in
+// comparison order: the first three become the major, minor and patch
+// numbers, so "2024.10.3" becomes 2024.10.3.  If there are more than three,
+// the remaining ones are packed in decimal into the patch number with two
+// digits for a week or day and six for a number, so "2024.10.16-1" in
+// "YYYY.0M.0D-MICRO" becomes 2024.10.16000001.  The modifier becomes the
+// pre-release.  An error is returned if a field does not fit, or if the
+// modifier is not a valid pre-release, such as "01" with a leading zero.
+func (c *CalVer) Version() (*Version, error) {
+	values := []uint64{uint64(c.Year), uint64(c.Month), uint64(c.Week), uint64(c.Day), c.Major, c.Minor, c.Micro}
+	v := &Version{PreRelease: Identifiers{}, Metadata: Identifiers{}}
+	if len(c.Modifier) > 0 {
+		for _, str := range strings.Split(c.Modifier, ".") {
+			id, err := newIdentifier(str, true)
+			if err != nil {
+				return nil, fmt.Errorf("modifier of version %s is not a valid pre-release: %s", c, err)
+			}
+			v.PreRelease = append(v.PreRelease, id)
+		}
+	}
+	components := []*uint64{&v.Major, &v.Minor, &v.Patch}
+
+	i := 0
+	for field := calverYear; field < calverModifier; field++ {
+		if !c.Format.hasFields[field] {
+			continue
+		}
+		value := values[field]
+		if i < versionComponents {
+			*components[i] = value
+			i++
+			continue
+		}
+		scale := uint64(math.Pow10(int(packedWidths[field])))
+		if value >= scale || v.Patch > (math.MaxUint64-value)/scale {
+			return nil, fmt.Errorf("version %s does not fit into a semantic version", c)
+		}
+		v.Patch = v.Patch*scale + value
+	}
+
+	return v, nil
+}
+
+// String returns c written in its format.
+func (c *CalVer) String() string {
+	var b strings.Builder
+	for i, token := range c.Format.tokens {
+		if token.field == calverModifier {
+			if len(c.Modifier) > 0 {
+				b.WriteString(c.Format.seps[i])
+				b.WriteString(c.Modifier)
+			}
+			continue
+		}
+		b.WriteString(c.Format.seps[i])
+
+		var n uint64
+		switch token.field {
+		case calverYear:
+			n = uint64(c.Year)
+			if token.short {
+				n -= 2000
+			}
+		case calverMonth:
+			n = uint64(c.Month)
+		case calverWeek:
+			n = uint64(c.Week)
+		case calverDay:
+			n = uint64(c.Day)
+		case calverMajor:
+			n = c.Major
+		case calverMinor:
+			n = c.Minor
+		case calverMicro:
+			n = c.Micro
+		}
+		s := strconv.FormatUint(n, 10)
+		for j := len(s); j < token.width; j++ {
+			b.WriteByte('0')
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// CalVers is an array of CalVer pointers for sorting.
+type CalVers []*CalVer
+
+func (s CalVers) Len() int {
+	return len(s)
+}
+
+func (s CalVers) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s CalVers) Less(i, j int) bool {
+	return s[i].Compare(s[j]) < 0
+}
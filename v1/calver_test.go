@@ -0,0 +1,260 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestCalVerFormat(t *testing.T) {
+	Convey("Test valid formats", t, func() {
+		for _, s := range []string{"YYYY.0M.0D", "YY.MM.MICRO", "YY.0M", "YYYY.0M.0D-MICRO", "0Y_0W", "YYYY.MINOR.MICRO-MODIFIER", "YYYY-MM-DD"} {
+			Convey(s, func() {
+				f, err := semver.NewCalVerFormat(s)
+				So(err, ShouldBeNil)
+				So(f.String(), ShouldEqual, s)
+			})
+		}
+	})
+
+	Convey("Test invalid formats", t, func() {
+		for _, s := range []string{"", "YYYY.", ".YYYY", "YYYY0M", "YYYY/0M", "MM.DD", "YYYY.MM.MM", "YYYY.YY", "YYYY.WW.DD", "YYYY.DD", "YYYY-MODIFIER.MICRO", "MODIFIER", "YYYY.FOO"} {
+			Convey(s, func() {
+				_, err := semver.NewCalVerFormat(s)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
+
+func TestNewCalVer(t *testing.T) {
+	Convey("Test parsing calendar versions", t, func() {
+		tests := []struct {
+			format, v string
+			expected  semver.CalVer
+		}{
+			{"YYYY.0M.0D", "2024.10.03", semver.CalVer{Year: 2024, Month: 10, Day: 3}},
+			{"YY.0M", "24.04", semver.CalVer{Year: 2024, Month: 4}},
+			{"YY.MM.MICRO", "24.4.12", semver.CalVer{Year: 2024, Month: 4, Micro: 12}},
+			{"0Y.0M", "06.11", semver.CalVer{Year: 2006, Month: 11}},
+			{"YYYY.0M.0D-MICRO", "2024.10.16-1", semver.CalVer{Year: 2024, Month: 10, Day: 16, Micro: 1}},
+			{"YYYY.0W", "2020.53", semver.CalVer{Year: 2020, Week: 53}},
+			{"YYYY.MINOR-MODIFIER", "2024.3-rc.1", semver.CalVer{Year: 2024, Minor: 3, Modifier: "rc.1"}},
+			{"YYYY.MINOR-MODIFIER", "2024.3", semver.CalVer{Year: 2024, Minor: 3}},
+		}
+		for _, tc := range tests {
+			Convey(tc.format+" "+tc.v, func() {
+				c, err := semver.NewCalVer(tc.format, tc.v)
+				So(err, ShouldBeNil)
+				So(c.Format.String(), ShouldEqual, tc.format)
+				tc.expected.Format = c.Format
+				So(*c, ShouldResemble, tc.expected)
+				So(c.String(), ShouldEqual, tc.v)
+			})
+		}
+	})
+
+	Convey("Test invalid calendar versions", t, func() {
+		tests := []struct {
+			format, v string
+		}{
+			{"YYYY.0M.0D", "2024.10.3"},
+			{"YYYY.0M.0D", "2024.13.03"},
+			{"YYYY.0M.0D", "2023.02.29"},
+			{"YYYY.0M.0D", "2024.10.03.1"},
+			{"YYYY.MM", "2024.010"},
+			{"YY.0M", "24.4"},
+			{"YY.0M", "024.04"},
+			{"YYYY.0W", "2020.54"},
+			{"YYYY.MICRO", "2024.01"},
+			{"YYYY.MINOR-MODIFIER", "2024.3-"},
+			{"YYYY.MINOR-MODIFIER", "2024.3-rc..1"},
+		}
+		for _, tc := range tests {
+			Convey(tc.format+" "+tc.v, func() {
+				_, err := semver.NewCalVer(tc.format, tc.v)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
+
+func TestCalVerCompare(t *testing.T) {
+	Convey("Test comparing calendar versions", t, func() {
+		f := semver.MustCalVerFormat(semver.NewCalVerFormat("YYYY.0M.0D-MICRO"))
+		tests := []struct {
+			lhs, rhs string
+			expected int
+		}{
+			{"2024.10.16-1", "2024.10.16-1", 0},
+			{"2024.10.16-1", "2024.10.16-2", -1},
+			{"2024.10.16-9", "2024.10.17-0", -1},
+			{"2024.11.01-0", "2024.10.31-5", 1},
+			{"2025.01.01-0", "2024.12.31-0", 1},
+		}
+		for _, tc := range tests {
+			Convey(tc.lhs+" <=> "+tc.rhs, func() {
+				lhs, err := f.Parse(tc.lhs)
+				So(err, ShouldBeNil)
+				rhs, err := f.Parse(tc.rhs)
+				So(err, ShouldBeNil)
+				So(lhs.Compare(rhs), ShouldEqual, tc.expected)
+				So(rhs.Compare(lhs), ShouldEqual, -tc.expected)
+			})
+		}
+	})
+
+	Convey("Test modifiers sort before releases", t, func() {
+		f := semver.MustCalVerFormat(semver.NewCalVerFormat("YY.MINOR-MODIFIER"))
+		var cs semver.CalVers
+		for _, s := range []string{"24.1", "24.1-rc.2", "23.9", "24.1-beta", "24.1-rc.10"} {
+			cs = append(cs, semver.MustCalVer(f.Parse(s)))
+		}
+		sort.Sort(cs)
+		var sorted []string
+		for _, c := range cs {
+			sorted = append(sorted, c.String())
+		}
+		So(sorted, ShouldResemble, []string{"23.9", "24.1-beta", "24.1-rc.2", "24.1-rc.10", "24.1"})
+	})
+}
+
+func TestCalVerBump(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	Convey("Test bumping calendar versions", t, func() {
+		tests := []struct {
+			format, v string
+			now       time.Time
+			expected  string
+		}{
+			{"YYYY.0M.0D-MICRO", "2024.10.16-1", day(2024, time.October, 16), "2024.10.16-2"},
+			{"YYYY.0M.0D-MICRO", "2024.10.16-1", day(2024, time.October, 17), "2024.10.17-0"},
+			{"YY.MM.MICRO", "24.4.3", day(2024, time.April, 30), "24.4.4"},
+			{"YY.MM.MICRO", "24.4.3", day(2025, time.January, 2), "25.1.0"},
+			{"YYYY.MINOR.MICRO", "2024.3.7", day(2024, time.December, 1), "2024.3.8"},
+			{"YYYY.MAJOR.MINOR", "2024.3.7", day(2024, time.December, 1), "2024.3.8"},
+			{"YYYY.MAJOR", "2024.3", day(2024, time.December, 1), "2024.4"},
+			{"YY.0M", "24.04", day(2024, time.October, 1), "24.10"},
+			{"YYYY.0W", "2020.52", day(2021, time.January, 1), "2020.53"},
+			{"YYYY.0M.0D-MODIFIER", "2024.10.16-rc1", day(2024, time.October, 18), "2024.10.18"},
+		}
+		for _, tc := range tests {
+			Convey(tc.format+" "+tc.v+" -> "+tc.expected, func() {
+				c := semver.MustCalVer(semver.NewCalVer(tc.format, tc.v))
+				next, err := c.Bump(tc.now)
+				So(err, ShouldBeNil)
+				So(next.String(), ShouldEqual, tc.expected)
+				So(next.Compare(c), ShouldEqual, 1)
+			})
+		}
+	})
+
+	Convey("Test invalid bumps", t, func() {
+		tests := []struct {
+			format, v string
+			now       time.Time
+		}{
+			{"YYYY.0M.0D", "2024.10.16", day(2024, time.October, 16)},
+			{"YYYY.0M.0D-MICRO", "2024.10.16-1", day(2024, time.October, 15)},
+			{"YY.MINOR", "24.1", day(1999, time.October, 15)},
+		}
+		for _, tc := range tests {
+			Convey(tc.format+" "+tc.v, func() {
+				c := semver.MustCalVer(semver.NewCalVer(tc.format, tc.v))
+				_, err := c.Bump(tc.now)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
+
+func TestCalVerVersion(t *testing.T) {
+	Convey("Test converting calendar versions", t, func() {
+		tests := []struct {
+			format, v, expected string
+		}{
+			{"YYYY.0M.0D", "2024.10.03", "2024.10.3"},
+			{"YY.0M", "24.04", "2024.4.0"},
+			{"YY.MM.MICRO", "24.4.12", "2024.4.12"},
+			{"YYYY.0M.0D-MICRO", "2024.10.16-1", "2024.10.16000001"},
+			{"YYYY.MINOR-MODIFIER", "2024.3-rc.1", "2024.3.0-rc.1"},
+		}
+		for _, tc := range tests {
+			Convey(tc.format+" "+tc.v, func() {
+				v, err := semver.MustCalVer(semver.NewCalVer(tc.format, tc.v)).Version()
+				So(err, ShouldBeNil)
+				So(v.String(), ShouldEqual, tc.expected)
+			})
+		}
+
+		_, err := semver.MustCalVer(semver.NewCalVer("YYYY.0M.0D-MICRO", "2024.10.16-1000000")).Version()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test calendar version modifiers round trip through Version", t, func() {
+		f := semver.MustCalVerFormat(semver.NewCalVerFormat("YYYY.MINOR-MODIFIER"))
+		tests := []struct {
+			v     string
+			valid bool
+		}{
+			{"2024.3-rc.1", true},
+			{"2024.3-0a.1-b", true},
+			{"2024.3-0", true},
+			{"2024.3-01", false},
+			{"2024.3-rc.007", false},
+			{"2024.3-18446744073709551616", false},
+		}
+		for _, tc := range tests {
+			Convey(tc.v, func() {
+				v, err := semver.MustCalVer(f.Parse(tc.v)).Version()
+				if !tc.valid {
+					So(err, ShouldNotBeNil)
+					return
+				}
+				So(err, ShouldBeNil)
+				parsed, err := semver.NewVersion(v.String())
+				So(err, ShouldBeNil)
+				So(parsed, ShouldResemble, v)
+			})
+		}
+	})
+
+	Convey("Test calendar versions sort with Versions", t, func() {
+		f := semver.MustCalVerFormat(semver.NewCalVerFormat("YYYY.0M.0D-MICRO"))
+		var vs semver.Versions
+		for _, s := range []string{"2024.10.16-10", "2024.10.17-0", "2024.10.16-2", "2024.09.30-0"} {
+			v, err := semver.MustCalVer(f.Parse(s)).Version()
+			So(err, ShouldBeNil)
+			vs = append(vs, v)
+		}
+		vs = append(vs, semver.New("1.2.3"))
+		sort.Sort(vs)
+		var sorted []string
+		for _, v := range vs {
+			sorted = append(sorted, v.String())
+		}
+		So(sorted, ShouldResemble, []string{"1.2.3", "2024.9.30000000", "2024.10.16000002", "2024.10.16000010", "2024.10.17000000"})
+	})
+}
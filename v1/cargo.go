@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// partialVersion is a version in which the minor and patch numbers may be
+// missing or wildcards, such as "1", "1.2" or "1.2.*".
+type partialVersion struct {
+	major, minor, patch uint64
+	// parts is the number of numeric components that were given.
+	parts      int
+	wildcard   bool
+	preRelease Identifiers
+}
+
+// parsePartialVersion parses a partial version.  Missing components and the
+// wildcards "*", "x" and "X" are both reported as not given.  A pre-release
+// is only allowed with all three components.
+func parsePartialVersion(s string) (partialVersion, error) {
+	var pv partialVersion
+	if len(s) == 0 {
+		return pv, errors.New("version string empty")
+	}
+	if i := strings.IndexRune(s, '+'); i != -1 {
+		s = s[:i]
+	}
+	if i := strings.IndexRune(s, '-'); i != -1 {
+		for _, str := range strings.Split(s[i+1:], ".") {
+			id, err := newIdentifier(str, true)
+			if err != nil {
+				return pv, err
+			}
+			pv.preRelease = append(pv.preRelease, id)
+		}
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > versionComponents {
+		return pv, fmt.Errorf("too many components in version %q", s)
+	}
+	for i, p := range parts {
+		if p == "*" || p == "x" || p == "X" {
+			pv.wildcard = true
+			continue
+		}
+		if pv.wildcard {
+			return pv, fmt.Errorf("version number after wildcard in %q", s)
+		}
+		if len(p) == 0 || !containsOnly(p, numbers) {
+			return pv, fmt.Errorf("invalid character(expected) found in version number %q", p)
+		}
+		if hasLeadingZeroes(p) {
+			return pv, fmt.Errorf("version number must not contain leading zeroes %q", p)
+		}
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return pv, err
+		}
+		switch i {
+		case 0:
+			pv.major = n
+		case 1:
+			pv.minor = n
+		default:
+			pv.patch = n
+		}
+		pv.parts++
+	}
+	if len(pv.preRelease) > 0 && pv.parts != versionComponents {
+		return pv, fmt.Errorf("pre-release requires a complete version in %q", s)
+	}
+
+	return pv, nil
+}
+
+// lower returns the lowest version matching pv.
+func (pv partialVersion) lower() *Version {
+	pre := pv.preRelease
+	if pre == nil {
+		pre = Identifiers{}
+	}
+	return &Version{Major: pv.major, Minor: pv.minor, Patch: pv.patch, PreRelease: pre, Metadata: Identifiers{}}
+}
+
+// next returns the lowest version above every version matching pv,
+// or nil if there is none.
+func (pv partialVersion) next() *Version {
+	switch pv.parts {
+	case 0:
+		return nil
+	case 1:
+		return &Version{Major: pv.major + 1, PreRelease: Identifiers{}, Metadata: Identifiers{}}
+	case 2:
+		return &Version{Major: pv.major, Minor: pv.minor + 1, PreRelease: Identifiers{}, Metadata: Identifiers{}}
+	default:
+		return nil
+	}
+}
+
+// ParseCargoRange parses a Cargo version requirement and returns a Range.
+// If the requirement could not be parsed an error is returned.
+//
+// A requirement is a comma separated list of comparators, all of which must
+// match:
+//   - "1.2.3", "^1.2.3" matches >=1.2.3 <2.0.0
+//   - "^0.2.3" matches >=0.2.3 <0.3.0 and "^0.0.3" matches >=0.0.3 <0.0.4
+//   - "~1.2.3" matches >=1.2.3 <1.3.0 and "~1" matches >=1.0.0 <2.0.0
+//   - "*", "1.*", "1.2.*" match any version with the given prefix
+//   - "=1.2.3" matches exactly 1.2.3
+//   - ">=1.2, <1.5" matches >=1.2.0 <1.5.0
+//
+// A pre-release version only matches if at least one comparator has the same
+// major, minor and patch numbers and a pre-release itself.
+func ParseCargoRange(s string) (Range, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, errors.New("requirement string empty")
+	}
+
+	var andFn Range
+	var preReleases []*Version
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		i := strings.IndexFunc(part, func(r rune) bool {
+			return !strings.ContainsRune("=<>^~", r)
+		})
+		if i == -1 {
+			return nil, fmt.Errorf("could not get version from comparator %q", part)
+		}
+		opStr, vStr := part[:i], strings.TrimSpace(part[i:])
+
+		pv, err := parsePartialVersion(vStr)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse Cargo requirement %q: %s", s, err)
+		}
+		vrs, err := cargoVersionRanges(opStr, pv)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse Cargo requirement %q: %s", s, err)
+		}
+		if len(pv.preRelease) > 0 {
+			preReleases = append(preReleases, pv.lower())
+		}
+
+		for _, vr := range vrs {
+			if andFn == nil {
+				andFn = vr.rangeFunc()
+			} else {
+				andFn = andFn.AND(vr.rangeFunc())
+			}
+		}
+	}
+	if andFn == nil {
+		andFn = func(*Version) bool { return true }
+	}
+
+	return andFn.AND(func(v *Version) bool {
+		if !v.IsPreRelease() {
+			return true
+		}
+		for _, pre := range preReleases {
+			if v.Major == pre.Major && v.Minor == pre.Minor && v.Patch == pre.Patch {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// cargoVersionRanges expands a single Cargo comparator into versionRanges.
+func cargoVersionRanges(opStr string, pv partialVersion) ([]*versionRange, error) {
+	if pv.wildcard && opStr != "" && opStr != "=" {
+		return nil, fmt.Errorf("wildcard is not allowed with %q", opStr)
+	}
+
+	lower, next := pv.lower(), pv.next()
+	between := func(upper *Version) []*versionRange {
+		if upper == nil {
+			return []*versionRange{{v: lower, c: compGE}}
+		}
+		return []*versionRange{{v: lower, c: compGE}, {v: upper, c: compLT}}
+	}
+
+	switch opStr {
+	case "", "^":
+		if pv.wildcard {
+			return between(next), nil
+		}
+		switch {
+		case pv.major > 0 || pv.parts == 1:
+			return between(&Version{Major: pv.major + 1, PreRelease: Identifiers{}, Metadata: Identifiers{}}), nil
+		case pv.minor > 0 || pv.parts == 2:
+			return between(&Version{Minor: pv.minor + 1, PreRelease: Identifiers{}, Metadata: Identifiers{}}), nil
+		default:
+			return between(&Version{Patch: pv.patch + 1, PreRelease: Identifiers{}, Metadata: Identifiers{}}), nil
+		}
+	case "~":
+		if pv.parts == 1 {
+			return between(next), nil
+		}
+		return between(&Version{Major: pv.major, Minor: pv.minor + 1, PreRelease: Identifiers{}, Metadata: Identifiers{}}), nil
+	case "=":
+		if pv.parts == versionComponents {
+			return []*versionRange{{v: lower, c: compEQ}}, nil
+		}
+		return between(next), nil
+	case ">=":
+		return []*versionRange{{v: lower, c: compGE}}, nil
+	case "<":
+		return []*versionRange{{v: lower, c: compLT}}, nil
+	case ">":
+		if pv.parts == versionComponents {
+			return []*versionRange{{v: lower, c: compGT}}, nil
+		}
+		return []*versionRange{{v: next, c: compGE}}, nil
+	case "<=":
+		if pv.parts == versionComponents {
+			return []*versionRange{{v: lower, c: compLE}}, nil
+		}
+		return []*versionRange{{v: next, c: compLT}}, nil
+	}
+
+	return nil, fmt.Errorf("could not parse comparator %q", opStr)
+}
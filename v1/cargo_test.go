@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestParseCargoRange(t *testing.T) {
+	type test struct {
+		v        string
+		expected bool
+	}
+	// The equivalences are taken from "Specifying Dependencies" in the Cargo book.
+	equivalent := []struct {
+		requirement string
+		bounds      string
+		data        []test
+	}{
+		// Caret requirements
+		{"1.2.3", ">=1.2.3 <2.0.0", []test{{"1.2.2", false}, {"1.9.0", true}}},
+		{"^1.2.3", ">=1.2.3 <2.0.0", []test{{"1.2.3", true}, {"2.0.0", false}}},
+		{"^1.2", ">=1.2.0 <2.0.0", []test{{"1.2.0", true}, {"1.1.9", false}}},
+		{"^1", ">=1.0.0 <2.0.0", []test{{"1.0.0", true}, {"1.99.0", true}}},
+		{"^0.2.3", ">=0.2.3 <0.3.0", []test{{"0.2.9", true}, {"0.3.0", false}}},
+		{"^0.2", ">=0.2.0 <0.3.0", []test{{"0.2.0", true}, {"0.3.0", false}}},
+		{"^0.0.3", ">=0.0.3 <0.0.4", []test{{"0.0.3", true}, {"0.0.4", false}}},
+		{"^0.0", ">=0.0.0 <0.1.0", []test{{"0.0.9", true}, {"0.1.0", false}}},
+		{"^0", ">=0.0.0 <1.0.0", []test{{"0.9.0", true}, {"1.0.0", false}}},
+		// Tilde requirements
+		{"~1.2.3", ">=1.2.3 <1.3.0", []test{{"1.2.9", true}, {"1.3.0", false}}},
+		{"~1.2", ">=1.2.0 <1.3.0", []test{{"1.2.0", true}, {"1.3.0", false}}},
+		{"~1", ">=1.0.0 <2.0.0", []test{{"1.9.0", true}, {"2.0.0", false}}},
+		// Wildcard requirements
+		{"*", ">=0.0.0", []test{{"0.0.0", true}, {"99.0.0", true}}},
+		{"1.*", ">=1.0.0 <2.0.0", []test{{"1.5.0", true}, {"2.0.0", false}}},
+		{"1.2.*", ">=1.2.0 <1.3.0", []test{{"1.2.5", true}, {"1.3.0", false}}},
+		{"1.x", ">=1.0.0 <2.0.0", []test{{"1.5.0", true}, {"0.9.0", false}}},
+		// Comparison requirements
+		{">= 1.2.0", ">=1.2.0", []test{{"1.2.0", true}, {"1.1.0", false}}},
+		{"> 1", ">=2.0.0", []test{{"1.9.9", false}, {"2.0.0", true}}},
+		{"> 1.2", ">=1.3.0", []test{{"1.2.9", false}, {"1.3.0", true}}},
+		{"< 2", "<2.0.0", []test{{"1.9.9", true}, {"2.0.0", false}}},
+		{"<= 1.2", "<1.3.0", []test{{"1.2.9", true}, {"1.3.0", false}}},
+		{"= 1.2.3", "=1.2.3", []test{{"1.2.3", true}, {"1.2.4", false}}},
+		{"=1.2", ">=1.2.0 <1.3.0", []test{{"1.2.7", true}, {"1.3.0", false}}},
+		// Multiple requirements
+		{">= 1.2, < 1.5", ">=1.2.0 <1.5.0", []test{{"1.4.9", true}, {"1.5.0", false}}},
+	}
+
+	Convey("Test Cargo requirements", t, func() {
+		for _, tc := range equivalent {
+			Convey(tc.requirement, func() {
+				r, err := semver.ParseCargoRange(tc.requirement)
+				So(err, ShouldBeNil)
+				bounds := semver.MustParseRange(tc.bounds)
+				for _, td := range tc.data {
+					Convey(td.v, func() {
+						v := semver.New(td.v)
+						So(r(v), ShouldEqual, td.expected)
+						So(r(v), ShouldEqual, bounds(v))
+					})
+				}
+			})
+		}
+	})
+
+	Convey("Test Cargo pre-release matching", t, func() {
+		tests := []struct {
+			requirement string
+			data        []test
+		}{
+			{">=1.2.3-alpha.3", []test{
+				{"1.2.3-alpha.3", true},
+				{"1.2.3-alpha.4", true},
+				{"1.2.3", true},
+				{"3.4.5-alpha.9", false},
+				{"3.4.5", true},
+			}},
+			{"^1.2.3", []test{
+				{"1.2.4-beta", false},
+				{"2.0.0-rc.1", false},
+			}},
+			{"~1.2.3-beta, <1.2.4-alpha.2", []test{
+				{"1.2.3-beta", true},
+				{"1.2.3-alpha", false},
+				{"1.2.4-alpha.1", true},
+				{"1.2.4-alpha.2", false},
+			}},
+			{"1.2.3+build.5", []test{
+				{"1.2.3", true},
+				{"1.9.0+other", true},
+			}},
+		}
+		for _, tc := range tests {
+			Convey(tc.requirement, func() {
+				r, err := semver.ParseCargoRange(tc.requirement)
+				So(err, ShouldBeNil)
+				for _, td := range tc.data {
+					Convey(td.v, func() {
+						So(r(semver.New(td.v)), ShouldEqual, td.expected)
+					})
+				}
+			})
+		}
+	})
+
+	Convey("Test invalid Cargo requirements", t, func() {
+		for _, s := range []string{"", ",", ">=1.0,", "==1.0", "=>1.0", ">=1.*", "1.*.3", "1.2.3.4", "01.2", "1.2-beta", ">=", "1 2"} {
+			Convey(s, func() {
+				_, err := semver.ParseCargoRange(s)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
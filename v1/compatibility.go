@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompatibilityRule is a named check of a client version against a server
+// version.  Check returns an empty string if the versions are compatible
+// under the rule, and the reason they are not otherwise.
+type CompatibilityRule struct {
+	Name  string
+	Check func(client, server *Version) string
+}
+
+// Incompatibility is a reason why a client and a server are not compatible.
+type Incompatibility struct {
+	Rule   string
+	Reason string
+}
+
+func (i Incompatibility) String() string {
+	return i.Rule + ": " + i.Reason
+}
+
+// CompatibilityError is returned by CompatibilityPolicy.Verify with every
+// reason why a client and a server are not compatible.
+type CompatibilityError struct {
+	Client          *Version
+	Server          *Version
+	Incompatibility []Incompatibility
+}
+
+func (e *CompatibilityError) Error() string {
+	reasons := make([]string, len(e.Incompatibility))
+	for i, inc := range e.Incompatibility {
+		reasons[i] = inc.String()
+	}
+	return fmt.Sprintf("client %s is not compatible with server %s: %s", e.Client, e.Server, strings.Join(reasons, "; "))
+}
+
+// CompatibilityPolicy is a list of rules, all of which a client and a server
+// must satisfy to be compatible.
+type CompatibilityPolicy []CompatibilityRule
+
+// DefaultCompatibilityPolicy extends CompatibleUnder with 0.x semantics and
+// pre-release handling: the client and server have the same major version,
+// and the same minor version for 0.x, the server minor version is at least
+// that of the client, and a pre-release is only compatible with the
+// identical pre-release.
+var DefaultCompatibilityPolicy = CompatibilityPolicy{SameMajor(), ServerMinorAtLeastClient(), IdenticalPreRelease()}
+
+// Check returns the reasons why client and server are not compatible under
+// p, which is empty if they are compatible.
+func (p CompatibilityPolicy) Check(client, server *Version) []Incompatibility {
+	var incompatibilities []Incompatibility
+	for _, rule := range p {
+		if reason := rule.Check(client, server); len(reason) > 0 {
+			incompatibilities = append(incompatibilities, Incompatibility{Rule: rule.Name, Reason: reason})
+		}
+	}
+	return incompatibilities
+}
+
+// Compatible returns true if client and server are compatible under p and false otherwise.
+func (p CompatibilityPolicy) Compatible(client, server *Version) bool {
+	return len(p.Check(client, server)) == 0
+}
+
+// Verify returns a *CompatibilityError if client and server are not
+// compatible under p, and nil otherwise.
+func (p CompatibilityPolicy) Verify(client, server *Version) error {
+	if incompatibilities := p.Check(client, server); len(incompatibilities) > 0 {
+		return &CompatibilityError{Client: client, Server: server, Incompatibility: incompatibilities}
+	}
+	return nil
+}
+
+// SameMajor requires the client and server to have the same major version.
+// As a 0.x version may break compatibility in any minor release, 0.x
+// versions must also have the same minor version.
+func SameMajor() CompatibilityRule {
+	return CompatibilityRule{
+		Name: "same-major",
+		Check: func(client, server *Version) string {
+			if client.Major != server.Major {
+				return fmt.Sprintf("major version %d differs from %d", client.Major, server.Major)
+			}
+			if client.Major == 0 && client.Minor != server.Minor {
+				return fmt.Sprintf("0.x minor version %d differs from %d", client.Minor, server.Minor)
+			}
+			return ""
+		},
+	}
+}
+
+// ServerMinorAtLeastClient requires the server to be at least the major and
+// minor version of the client, as the client may use features added in its
+// minor version.
+func ServerMinorAtLeastClient() CompatibilityRule {
+	return CompatibilityRule{
+		Name: "server-minor",
+		Check: func(client, server *Version) string {
+			if server.Major < client.Major || server.Major == client.Major && server.Minor < client.Minor {
+				return fmt.Sprintf("server %d.%d is older than client %d.%d", server.Major, server.Minor, client.Major, client.Minor)
+			}
+			return ""
+		},
+	}
+}
+
+// IdenticalPreRelease requires a pre-release client or server to have the
+// same precedence as the other, so that a pre-release is only compatible with
+// the identical pre-release.  Build metadata is ignored.
+func IdenticalPreRelease() CompatibilityRule {
+	return CompatibilityRule{
+		Name: "identical-pre-release",
+		Check: func(client, server *Version) string {
+			if (client.IsPreRelease() || server.IsPreRelease()) && client.Compare(server) != 0 {
+				pre := client
+				if !pre.IsPreRelease() {
+					pre = server
+				}
+				return fmt.Sprintf("pre-release %s is only compatible with itself", pre)
+			}
+			return ""
+		},
+	}
+}
+
+// CompatibleRange requires the server to satisfy a Range, such as one
+// parsed from a range that the client declares it supports.
+func CompatibleRange(name string, r Range) CompatibilityRule {
+	return CompatibilityRule{
+		Name: name,
+		Check: func(client, server *Version) string {
+			if !r(server) {
+				return fmt.Sprintf("server %s is not in the supported range", server)
+			}
+			return ""
+		},
+	}
+}
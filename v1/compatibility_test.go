@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestCompatibilityRules(t *testing.T) {
+	tests := []struct {
+		rule       semver.CompatibilityRule
+		client     string
+		server     string
+		compatible bool
+	}{
+		{semver.SameMajor(), "1.2.0", "1.9.0", true},
+		{semver.SameMajor(), "1.2.0", "2.0.0", false},
+		{semver.SameMajor(), "0.3.1", "0.3.9", true},
+		{semver.SameMajor(), "0.3.1", "0.4.0", false},
+		{semver.ServerMinorAtLeastClient(), "1.2.9", "1.2.0", true},
+		{semver.ServerMinorAtLeastClient(), "1.2.0", "1.3.0", true},
+		{semver.ServerMinorAtLeastClient(), "1.3.0", "1.2.9", false},
+		{semver.ServerMinorAtLeastClient(), "2.0.0", "1.9.0", false},
+		{semver.ServerMinorAtLeastClient(), "1.9.0", "2.0.0", true},
+		{semver.IdenticalPreRelease(), "1.2.0", "1.3.0", true},
+		{semver.IdenticalPreRelease(), "1.2.0-rc.1", "1.2.0-rc.1+build.5", true},
+		{semver.IdenticalPreRelease(), "1.2.0-rc.1", "1.2.0-rc.2", false},
+		{semver.IdenticalPreRelease(), "1.2.0", "1.2.0-rc.1", false},
+		{semver.IdenticalPreRelease(), "1.2.0-rc.1", "1.2.0", false},
+		{semver.CompatibleRange("declared", semver.MustParseRange(">=1.2.0 <1.5.0")), "1.0.0", "1.4.9", true},
+		{semver.CompatibleRange("declared", semver.MustParseRange(">=1.2.0 <1.5.0")), "1.0.0", "1.5.0", false},
+	}
+
+	Convey("Test compatibility rules", t, func() {
+		for _, tc := range tests {
+			Convey(tc.rule.Name+" "+tc.client+" "+tc.server, func() {
+				reason := tc.rule.Check(semver.New(tc.client), semver.New(tc.server))
+				So(reason == "", ShouldEqual, tc.compatible)
+			})
+		}
+	})
+}
+
+func TestCompatibilityPolicy(t *testing.T) {
+	Convey("Test the default compatibility policy", t, func() {
+		tests := []struct {
+			client, server string
+			rules          []string
+		}{
+			{"1.2.3", "1.2.0", nil},
+			{"1.2.3", "1.4.0", nil},
+			{"1.3.0", "1.2.0", []string{"server-minor"}},
+			{"2.0.0", "1.9.0", []string{"same-major", "server-minor"}},
+			{"0.2.0", "0.3.0", []string{"same-major"}},
+			{"1.2.0-beta.1", "1.2.0-beta.2", []string{"identical-pre-release"}},
+			{"1.2.0-beta.1", "1.2.0-beta.1", nil},
+			{"2.0.0-rc.1", "1.9.0", []string{"same-major", "server-minor", "identical-pre-release"}},
+		}
+		for _, tc := range tests {
+			Convey(tc.client+" "+tc.server, func() {
+				client, server := semver.New(tc.client), semver.New(tc.server)
+				var rules []string
+				for _, inc := range semver.DefaultCompatibilityPolicy.Check(client, server) {
+					So(inc.Reason, ShouldNotBeEmpty)
+					rules = append(rules, inc.Rule)
+				}
+				So(rules, ShouldResemble, tc.rules)
+				So(semver.DefaultCompatibilityPolicy.Compatible(client, server), ShouldEqual, tc.rules == nil)
+
+				if tc.rules == nil {
+					So(client.CompatibleUnder(server), ShouldBeTrue)
+				}
+			})
+		}
+	})
+
+	Convey("Test verifying compatibility", t, func() {
+		policy := semver.CompatibilityPolicy{semver.SameMajor(), semver.ServerMinorAtLeastClient()}
+		So(policy.Verify(semver.New("1.2.0"), semver.New("1.2.5")), ShouldBeNil)
+
+		err := policy.Verify(semver.New("1.3.0"), semver.New("1.2.5"))
+		So(err, ShouldNotBeNil)
+		ce, ok := err.(*semver.CompatibilityError)
+		So(ok, ShouldBeTrue)
+		So(ce.Client.String(), ShouldEqual, "1.3.0")
+		So(ce.Server.String(), ShouldEqual, "1.2.5")
+		So(ce.Incompatibility, ShouldResemble, []semver.Incompatibility{{Rule: "server-minor", Reason: "server 1.2 is older than client 1.3"}})
+		So(err.Error(), ShouldEqual, "client 1.3.0 is not compatible with server 1.2.5: server-minor: server 1.2 is older than client 1.3")
+	})
+
+	Convey("Test custom rules", t, func() {
+		sameBuild := semver.CompatibilityRule{
+			Name: "same-build",
+			Check: func(client, server *semver.Version) string {
+				if client.Metadata.String() != server.Metadata.String() {
+					return "build metadata differs"
+				}
+				return ""
+			},
+		}
+		policy := append(semver.CompatibilityPolicy{sameBuild}, semver.DefaultCompatibilityPolicy...)
+		So(policy.Compatible(semver.New("1.2.0+a"), semver.New("1.2.0+a")), ShouldBeTrue)
+		So(policy.Compatible(semver.New("1.2.0+a"), semver.New("1.2.0+b")), ShouldBeFalse)
+		So(semver.CompatibilityPolicy{}.Compatible(semver.New("1.0.0"), semver.New("9.0.0")), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,371 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Stability is the stability of a Composer package version.
+type Stability int
+
+// The Composer stabilities, from least to most stable.
+const (
+	StabilityDev Stability = iota
+	StabilityAlpha
+	StabilityBeta
+	StabilityRC
+	StabilityStable
+)
+
+var stabilityNames = []string{"dev", "alpha", "beta", "RC", "stable"}
+
+// ParseStability parses a Composer stability name, ignoring case.
+func ParseStability(s string) (Stability, error) {
+	for i, name := range stabilityNames {
+		if strings.EqualFold(s, name) {
+			return Stability(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown stability %q", s)
+}
+
+func (s Stability) String() string {
+	if s < StabilityDev || s > StabilityStable {
+		return "Stability(" + strconv.Itoa(int(s)) + ")"
+	}
+	return stabilityNames[s]
+}
+
+// StabilityOf returns the stability of v, which is read from its first
+// pre-release identifier: "alpha" or "a", "beta" or "b" and "rc" map onto
+// the corresponding stability, ignoring case.  A version without a
+// pre-release is stable and any other pre-release is dev.
+func StabilityOf(v *Version) Stability {
+	if !v.IsPreRelease() {
+		return StabilityStable
+	}
+	if v.PreRelease[0].IsNum {
+		return StabilityDev
+	}
+	switch strings.ToLower(v.PreRelease[0].Str) {
+	case "alpha", "a":
+		return StabilityAlpha
+	case "beta", "b":
+		return StabilityBeta
+	case "rc":
+		return StabilityRC
+	}
+	return StabilityDev
+}
+
+var (
+	composerOrPattern     = regexp.MustCompile(`\s*\|\|?\s*`)
+	composerHyphenPattern = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+	composerVersionRegex  = regexp.MustCompile(`(?i)^v?([0-9]+|[*xX])(?:\.([0-9]+|[*xX]))?(?:\.([0-9]+|[*xX]))?(?:\.([0-9]+|[*xX]))?` +
+		`(?:[._-]?(stable|beta|b|rc|alpha|a)((?:[.-]?[0-9]+)*))?([.-]?dev)?$`)
+	composerOperators = []string{">=", "<=", "<>", "!=", "==", ">", "<", "=", "~", "^"}
+)
+
+// ParseComposerRange parses a Composer version constraint and returns a Range
+// that only matches stable versions, unless the constraint has a stability
+// flag.  It is equivalent to ParseComposerRangeWithStability with StabilityStable.
+func ParseComposerRange(s string) (Range, error) {
+	return ParseComposerRangeWithStability(s, StabilityStable)
+}
+
+// ParseComposerRangeWithStability parses a Composer version constraint and
+// returns a Range.  If the constraint could not be parsed an error is returned.
+//
+// Valid constraints are:
+//   - "1.0.2", ">=1.0", "<1.1", "!=1.0.5", "<>1.0.5"
+//   - "1.0.*" matches >=1.0.0 <1.1.0
+//   - "~1.2" matches >=1.2.0 <2.0.0 and "~1.2.3" matches >=1.2.3 <1.3.0
+//   - "^1.2.3" matches >=1.2.3 <2.0.0 and "^0.3" matches >=0.3.0 <0.4.0
+//   - "1.0 - 2.0" matches >=1.0.0 <2.1.0
+//
+// Constraints separated by comma or space must all match, and groups can be
+// separated by "||" or "|".
+//
+// A version only matches if StabilityOf(v) is at least minimumStability.  A
+// stability flag such as "@beta", or an explicit stability in a version of
+// the constraint such as ">=1.0-beta2", lowers the minimum.  Versions in the
+// constraint are written as in Composer: "1.0-beta2" is 1.0.0-beta.2 and
+// "1.0-dev" is 1.0.0-dev.
+func ParseComposerRangeWithStability(s string, minimumStability Stability) (Range, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, errors.New("constraint string empty")
+	}
+
+	var orFn Range
+	for _, orPart := range composerOrPattern.Split(strings.TrimSpace(s), -1) {
+		var andFn Range
+		for _, term := range splitComposerTerms(orPart) {
+			if i := strings.IndexRune(term, '@'); i != -1 {
+				flag, err := ParseStability(term[i+1:])
+				if err != nil {
+					return nil, fmt.Errorf("could not parse Composer constraint %q: %s", s, err)
+				}
+				if flag < minimumStability {
+					minimumStability = flag
+				}
+				term = term[:i]
+				if len(term) == 0 {
+					term = "*"
+				}
+			}
+
+			vrs, stability, err := composerVersionRanges(term)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse Composer constraint %q: %s", s, err)
+			}
+			if stability < minimumStability {
+				minimumStability = stability
+			}
+			for _, vr := range vrs {
+				if andFn == nil {
+					andFn = vr.rangeFunc()
+				} else {
+					andFn = andFn.AND(vr.rangeFunc())
+				}
+			}
+		}
+		if andFn == nil {
+			return nil, fmt.Errorf("empty constraint in %q", s)
+		}
+		if orFn == nil {
+			orFn = andFn
+		} else {
+			orFn = orFn.OR(andFn)
+		}
+	}
+
+	return orFn.AND(func(v *Version) bool {
+		return StabilityOf(v) >= minimumStability
+	}), nil
+}
+
+// splitComposerTerms splits an AND group by commas and spaces, keeping
+// operators with their version and hyphen ranges together.
+func splitComposerTerms(s string) []string {
+	fields := strings.Fields(strings.Replace(s, ",", " ", -1))
+	var terms []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case i+2 < len(fields) && fields[i+1] == "-":
+			f = f + " - " + fields[i+2]
+			i += 2
+		case containsOnly(f, "<>=!~^") && i+1 < len(fields):
+			f += fields[i+1]
+			i++
+		}
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+// composerVersion is a parsed version of a Composer constraint.
+type composerVersion struct {
+	partialVersion
+	stability Stability
+	explicit  bool
+}
+
+func parseComposerVersion(s string) (composerVersion, error) {
+	var cv composerVersion
+	m := composerVersionRegex.FindStringSubmatch(s)
+	if m == nil {
+		return cv, fmt.Errorf("invalid version string %q", s)
+	}
+
+	for i, p := range m[1:5] {
+		if p == "" {
+			break
+		}
+		if p == "*" || p == "x" || p == "X" {
+			cv.wildcard = true
+			continue
+		}
+		if cv.wildcard {
+			return cv, fmt.Errorf("version number after wildcard in %q", s)
+		}
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return cv, err
+		}
+		switch i {
+		case 0:
+			cv.major = n
+		case 1:
+			cv.minor = n
+		case 2:
+			cv.patch = n
+		default:
+			if n != 0 {
+				return cv, fmt.Errorf("fourth version number must be zero in %q", s)
+			}
+			continue
+		}
+		cv.parts++
+	}
+	cv.preRelease = Identifiers{}
+	cv.stability = StabilityStable
+
+	if modifier := strings.ToLower(m[5]); modifier != "" && modifier != "stable" {
+		switch modifier {
+		case "a", "alpha":
+			cv.preRelease = Identifiers{{Str: "alpha"}}
+		case "b", "beta":
+			cv.preRelease = Identifiers{{Str: "beta"}}
+		default:
+			cv.preRelease = Identifiers{{Str: "rc"}}
+		}
+		for _, n := range strings.FieldsFunc(m[6], func(r rune) bool { return r == '.' || r == '-' }) {
+			num, err := strconv.ParseUint(n, 10, 64)
+			if err != nil {
+				return cv, err
+			}
+			cv.preRelease = append(cv.preRelease, Identifier{Num: num, IsNum: true})
+		}
+	}
+	if m[7] != "" {
+		cv.preRelease = append(cv.preRelease, Identifier{Str: "dev"})
+	}
+	if m[5] != "" || m[7] != "" {
+		if cv.wildcard {
+			return cv, fmt.Errorf("stability is not allowed with a wildcard in %q", s)
+		}
+		cv.explicit = true
+		cv.stability = StabilityOf(&Version{PreRelease: cv.preRelease})
+	}
+
+	return cv, nil
+}
+
+// floor returns the lowest version of cv, including pre-releases unless
+// cv has an explicit stability.
+func (cv composerVersion) floor() *Version {
+	v := cv.lower()
+	if !cv.explicit {
+		v.PreRelease = Identifiers{{Num: 0, IsNum: true}}
+	}
+	return v
+}
+
+// excludingPreReleases returns the lowest pre-release of v, so that a range ending at v
+// excludes the pre-releases of v.
+func excludingPreReleases(v *Version) *Version {
+	return &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, PreRelease: Identifiers{{Num: 0, IsNum: true}}, Metadata: Identifiers{}}
+}
+
+// composerVersionRanges expands a single Composer term into versionRanges,
+// returning the stability the term explicitly asks for.
+func composerVersionRanges(term string) ([]*versionRange, Stability, error) {
+	if m := composerHyphenPattern.FindStringSubmatch(term); m != nil {
+		from, err := parseComposerVersion(m[1])
+		if err != nil {
+			return nil, 0, err
+		}
+		to, err := parseComposerVersion(m[2])
+		if err != nil {
+			return nil, 0, err
+		}
+		if from.wildcard || to.wildcard {
+			return nil, 0, fmt.Errorf("wildcard is not allowed in hyphen range %q", term)
+		}
+		upper := &versionRange{v: to.lower(), c: compLE}
+		if to.parts < versionComponents && !to.explicit {
+			upper = &versionRange{v: excludingPreReleases(to.next()), c: compLT}
+		}
+		stability := from.stability
+		if to.stability < stability {
+			stability = to.stability
+		}
+		return []*versionRange{{v: from.floor(), c: compGE}, upper}, stability, nil
+	}
+
+	var opStr string
+	for _, op := range composerOperators {
+		if strings.HasPrefix(term, op) {
+			opStr = op
+			break
+		}
+	}
+	cv, err := parseComposerVersion(term[len(opStr):])
+	if err != nil {
+		return nil, 0, err
+	}
+	if cv.wildcard && opStr != "" && opStr != "=" && opStr != "==" {
+		return nil, 0, fmt.Errorf("wildcard is not allowed with %q", opStr)
+	}
+
+	floor := cv.floor()
+	between := func(upper *Version) []*versionRange {
+		if upper == nil {
+			return []*versionRange{{v: floor, c: compGE}}
+		}
+		return []*versionRange{{v: floor, c: compGE}, {v: excludingPreReleases(upper), c: compLT}}
+	}
+	var vrs []*versionRange
+
+	switch opStr {
+	case "", "=", "==":
+		if cv.wildcard {
+			vrs = between(cv.next())
+		} else {
+			vrs = []*versionRange{{v: cv.lower(), c: compEQ}}
+		}
+	case "!=", "<>":
+		vrs = []*versionRange{{v: cv.lower(), c: compNE}}
+	case ">=":
+		vrs = []*versionRange{{v: floor, c: compGE}}
+	case ">":
+		vrs = []*versionRange{{v: cv.lower(), c: compGT}}
+	case "<=":
+		vrs = []*versionRange{{v: cv.lower(), c: compLE}}
+	case "<":
+		if cv.explicit {
+			vrs = []*versionRange{{v: cv.lower(), c: compLT}}
+		} else {
+			vrs = []*versionRange{{v: floor, c: compLT}}
+		}
+	case "~":
+		if cv.parts == 1 {
+			vrs = between(cv.next())
+		} else if cv.parts == 2 {
+			vrs = between(&Version{Major: cv.major + 1})
+		} else {
+			vrs = between(&Version{Major: cv.major, Minor: cv.minor + 1})
+		}
+	case "^":
+		switch {
+		case cv.major > 0 || cv.parts == 1:
+			vrs = between(&Version{Major: cv.major + 1})
+		case cv.minor > 0 || cv.parts == 2:
+			vrs = between(&Version{Minor: cv.minor + 1})
+		default:
+			vrs = between(&Version{Patch: cv.patch + 1})
+		}
+	}
+
+	return vrs, cv.stability, nil
+}
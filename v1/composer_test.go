@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestStability(t *testing.T) {
+	Convey("Test parsing stabilities", t, func() {
+		for _, s := range []string{"dev", "alpha", "beta", "RC", "stable"} {
+			st, err := semver.ParseStability(s)
+			So(err, ShouldBeNil)
+			So(st.String(), ShouldEqual, s)
+		}
+		st, err := semver.ParseStability("rc")
+		So(err, ShouldBeNil)
+		So(st, ShouldEqual, semver.StabilityRC)
+		_, err = semver.ParseStability("gamma")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test stability of versions", t, func() {
+		tests := []struct {
+			v        string
+			expected semver.Stability
+		}{
+			{"1.0.0", semver.StabilityStable},
+			{"1.0.0+build.1", semver.StabilityStable},
+			{"1.0.0-RC.1", semver.StabilityRC},
+			{"1.0.0-beta.2", semver.StabilityBeta},
+			{"1.0.0-b", semver.StabilityBeta},
+			{"1.0.0-alpha", semver.StabilityAlpha},
+			{"1.0.0-dev", semver.StabilityDev},
+			{"1.0.0-snapshot", semver.StabilityDev},
+			{"1.0.0-0", semver.StabilityDev},
+		}
+		for _, tc := range tests {
+			Convey(tc.v, func() {
+				So(semver.StabilityOf(semver.New(tc.v)), ShouldEqual, tc.expected)
+			})
+		}
+	})
+}
+
+func TestParseComposerRange(t *testing.T) {
+	type test struct {
+		v        string
+		expected bool
+	}
+	// The equivalences are taken from "Writing Version Constraints" in the Composer documentation.
+	equivalent := []struct {
+		constraint string
+		bounds     string
+		data       []test
+	}{
+		{"1.0.2", "=1.0.2", []test{{"1.0.2", true}, {"1.0.3", false}}},
+		{"v1.0", "=1.0.0", []test{{"1.0.0", true}, {"1.0.1", false}}},
+		{"==1.0.0.0", "=1.0.0", []test{{"1.0.0", true}, {"1.0.1", false}}},
+		{">=1.0", ">=1.0.0", []test{{"1.0.0", true}, {"0.9.9", false}}},
+		{">1.0", ">1.0.0", []test{{"1.0.0", false}, {"1.0.1", true}}},
+		{"<1.1", "<1.1.0", []test{{"1.0.9", true}, {"1.1.0", false}}},
+		{"<=1.1", "<=1.1.0", []test{{"1.1.0", true}, {"1.1.1", false}}},
+		{"!=1.0.5", "!=1.0.5", []test{{"1.0.5", false}, {"1.0.6", true}}},
+		{"<>1.0.5", "!=1.0.5", []test{{"1.0.5", false}, {"1.0.4", true}}},
+		{">=1.0 <1.1 || >=1.2", ">=1.0.0 <1.1.0 || >=1.2.0", []test{{"1.0.5", true}, {"1.1.0", false}, {"1.2.0", true}}},
+		{">=1.0,<1.1|>=1.2", ">=1.0.0 <1.1.0 || >=1.2.0", []test{{"1.0.5", true}, {"1.1.5", false}, {"3.0.0", true}}},
+		{">= 1.0, < 1.1", ">=1.0.0 <1.1.0", []test{{"1.0.5", true}, {"1.1.0", false}}},
+		// Hyphenated ranges
+		{"1.0 - 2.0", ">=1.0.0 <2.1.0", []test{{"2.0.9", true}, {"2.1.0", false}}},
+		{"1.0.0 - 2.1.0", ">=1.0.0 <=2.1.0", []test{{"2.1.0", true}, {"2.1.1", false}}},
+		// Wildcards
+		{"1.0.*", ">=1.0.0 <1.1.0", []test{{"1.0.9", true}, {"1.1.0", false}}},
+		{"1.x", ">=1.0.0 <2.0.0", []test{{"1.9.0", true}, {"2.0.0", false}}},
+		{"*", ">=0.0.0", []test{{"0.0.0", true}, {"9.0.0", true}}},
+		// Tilde ranges
+		{"~1.2", ">=1.2.0 <2.0.0", []test{{"1.9.0", true}, {"2.0.0", false}}},
+		{"~1.2.3", ">=1.2.3 <1.3.0", []test{{"1.2.9", true}, {"1.3.0", false}}},
+		{"~1", ">=1.0.0 <2.0.0", []test{{"1.9.0", true}, {"2.0.0", false}}},
+		// Caret ranges
+		{"^1.2.3", ">=1.2.3 <2.0.0", []test{{"1.9.0", true}, {"2.0.0", false}}},
+		{"^0.3", ">=0.3.0 <0.4.0", []test{{"0.3.9", true}, {"0.4.0", false}}},
+		{"^0.0.3", ">=0.0.3 <0.0.4", []test{{"0.0.3", true}, {"0.0.4", false}}},
+	}
+
+	Convey("Test Composer constraints", t, func() {
+		for _, tc := range equivalent {
+			Convey(tc.constraint, func() {
+				r, err := semver.ParseComposerRange(tc.constraint)
+				So(err, ShouldBeNil)
+				bounds := semver.MustParseRange(tc.bounds)
+				for _, td := range tc.data {
+					Convey(td.v, func() {
+						v := semver.New(td.v)
+						So(r(v), ShouldEqual, td.expected)
+						So(r(v), ShouldEqual, bounds(v))
+					})
+				}
+			})
+		}
+	})
+
+	Convey("Test Composer stability", t, func() {
+		tests := []struct {
+			constraint string
+			minimum    semver.Stability
+			data       []test
+		}{
+			{"^1.0", semver.StabilityStable, []test{
+				{"1.2.0", true},
+				{"1.2.0-RC.1", false},
+				{"2.0.0-beta.1", false},
+			}},
+			{"^1.0@beta", semver.StabilityStable, []test{
+				{"1.2.0-RC.1", true},
+				{"1.2.0-beta.1", true},
+				{"1.2.0-alpha.1", false},
+				{"2.0.0-beta.1", false},
+			}},
+			{">=1.0 @dev", semver.StabilityStable, []test{
+				{"1.2.0-dev", true},
+				{"1.0.0-alpha", true},
+				{"0.9.0-dev", false},
+			}},
+			{">=1.0-beta2", semver.StabilityStable, []test{
+				{"1.0.0-beta.1", false},
+				{"1.0.0-beta.2", true},
+				{"1.0.0-beta.10", true},
+				{"1.0.0-rc.1", true},
+				{"1.1.0-alpha.1", false},
+			}},
+			{"1.0.0-RC1", semver.StabilityStable, []test{
+				{"1.0.0-rc.1", true},
+				{"1.0.0", false},
+			}},
+			{"<2.0", semver.StabilityDev, []test{
+				{"1.9.9-beta", true},
+				{"2.0.0-alpha", false},
+				{"2.0.0", false},
+			}},
+			{"~1.2", semver.StabilityRC, []test{
+				{"1.2.0-RC.1", true},
+				{"1.3.0-beta.1", false},
+			}},
+			{"1.0.0-dev - 2.0", semver.StabilityStable, []test{
+				{"1.0.0-dev", true},
+				{"2.0.5-alpha", true},
+				{"2.1.0-alpha", false},
+			}},
+		}
+		for _, tc := range tests {
+			Convey(tc.constraint, func() {
+				r, err := semver.ParseComposerRangeWithStability(tc.constraint, tc.minimum)
+				So(err, ShouldBeNil)
+				for _, td := range tc.data {
+					Convey(td.v, func() {
+						So(r(semver.New(td.v)), ShouldEqual, td.expected)
+					})
+				}
+			})
+		}
+	})
+
+	Convey("Test invalid Composer constraints", t, func() {
+		for _, s := range []string{"", " || ", "1.0 ||", "foo", ">=1.*", "~*", "1.*.3", "1.0.0.1", "1.0@gamma", "1.*-beta", "1.* - 2.0", ">=1.0-p1"} {
+			Convey(s, func() {
+				_, err := semver.ParseComposerRange(s)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"sort"
+	"strings"
+)
+
+// Constraint is a parsed range, in the syntax of ParseRange, that keeps its
+// structure so that it can be inspected, for example to explain why a
+// version does not satisfy it.  The zero Constraint matches any version,
+// like the empty range.
+type Constraint struct {
+	str string
+	// groups are the OR'ed branches of the constraint, each a list of
+	// AND'ed versionRanges, with wildcards and partial versions expanded.
+	groups [][]*versionRange
+	// table is groups compiled to sorted and disjoint intervals.
+	table []interval
+}
+
+// ParseConstraint parses a range in the syntax of ParseRange and returns a
+// Constraint.  If the range could not be parsed an error is returned.
+func ParseConstraint(s string) (*Constraint, error) {
+	groups, err := parseRangeGroups(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Constraint{str: s, groups: groups, table: intervals(groups)}, nil
+}
+
+// MustParseConstraint is like ParseConstraint but panics if the range cannot be parsed.
+func MustParseConstraint(s string) *Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(`semver: ParseConstraint(` + s + `): ` + err.Error())
+	}
+	return c
+}
+
+// Set parses and updates c from the given range string. Implements flag.Value
+func (c *Constraint) Set(s string) error {
+	parsed, err := ParseConstraint(s)
+	if err != nil {
+		return err
+	}
+	*c = *parsed
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c Constraint) MarshalText() ([]byte, error) {
+	return []byte(c.str), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (c *Constraint) UnmarshalText(text []byte) error {
+	return c.Set(string(text))
+}
+
+// String returns the range that c was parsed from.
+func (c Constraint) String() string {
+	return c.str
+}
+
+// branches returns the OR'ed branches of c.
+func (c *Constraint) branches() [][]*versionRange {
+	if c.groups == nil {
+		return [][]*versionRange{nil}
+	}
+	return c.groups
+}
+
+// Range returns a Range that matches the versions that satisfy c, which
+// it looks up in the intervals that c is compiled to with a binary search.
+func (c *Constraint) Range() Range {
+	table := c.intervals()
+	if len(table) == 1 {
+		// Most ranges are a single interval, which needs no search.
+		lower, upper := table[0].lower, table[0].upper
+		return func(v *Version) bool {
+			if cmp := v.Compare(lower.v); cmp < 0 || cmp == 0 && !lower.inclusive {
+				return false
+			}
+			if upper.v == nil {
+				return true
+			}
+			cmp := v.Compare(upper.v)
+			return cmp < 0 || cmp == 0 && upper.inclusive
+		}
+	}
+	return func(v *Version) bool {
+		i := searchIntervals(table, v)
+		return i < len(table) && !table[i].below(v)
+	}
+}
+
+// FilterVersions returns the versions of sorted, which must be sorted in
+// ascending order, that satisfy c.  As c is compiled to m intervals, it
+// runs in O(m log n + k) for n versions of which k satisfy c.  This is only
+// O(log n + k) if m is taken as a constant: a range with many OR'ed branches
+// or exclusions takes a binary search for each of its intervals.
+func (c *Constraint) FilterVersions(sorted Versions) Versions {
+	filtered := Versions{}
+	rest := sorted
+	for _, i := range c.intervals() {
+		lo := sort.Search(len(rest), func(j int) bool {
+			return !i.below(rest[j])
+		})
+		hi := lo + sort.Search(len(rest)-lo, func(j int) bool {
+			return i.above(rest[lo+j])
+		})
+		filtered = append(filtered, rest[lo:hi]...)
+		rest = rest[hi:]
+	}
+	return filtered
+}
+
+// formatBranch returns a branch of a Constraint in the syntax of ParseRange.
+func formatBranch(group []*versionRange) string {
+	if len(group) == 0 {
+		return "*"
+	}
+	terms := make([]string, len(group))
+	for i, vr := range group {
+		terms[i] = vr.String()
+	}
+	return strings.Join(terms, " ")
+}
+
+// intervals returns the versions that satisfy c as sorted, disjoint and
+// non-empty intervals.
+func (c *Constraint) intervals() []interval {
+	if c.groups == nil {
+		return intervals(c.branches())
+	}
+	return c.table
+}
+
+// MinVersion returns the lowest version that satisfies c, or nil if no
+// version does.  As ranges match pre-releases, the lowest version above
+// 1.2.3 is 1.2.4-0, and the lowest version of "*" is 0.0.0-0.
+func MinVersion(c *Constraint) *Version {
+	for _, i := range c.intervals() {
+		if v := i.first(); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// MaxVersion returns the upper bound of the versions that satisfy c and
+// whether the bound itself satisfies c, which it does not for an exclusive
+// bound such as "<2.0.0".  The bound is nil if c is unbounded above or if no
+// version satisfies c.
+func MaxVersion(c *Constraint) (*Version, bool) {
+	is := c.intervals()
+	if len(is) == 0 || is[len(is)-1].upper.v == nil {
+		return nil, false
+	}
+	upper := is[len(is)-1].upper
+	return upper.v.Clone(), upper.inclusive
+}
+
+// GreaterThanRange returns true if v is higher than every version that
+// satisfies c.  It returns false if no version satisfies c.
+func GreaterThanRange(v *Version, c *Constraint) bool {
+	is := c.intervals()
+	return len(is) > 0 && is[len(is)-1].above(v)
+}
+
+// LessThanRange returns true if v is lower than every version that
+// satisfies c.  It returns false if no version satisfies c.
+func LessThanRange(v *Version, c *Constraint) bool {
+	is := c.intervals()
+	return len(is) > 0 && is[0].below(v)
+}
+
+// Outside returns true if v is either higher or lower than every version
+// that satisfies c.  A version that does not satisfy c but lies between two
+// of its versions, such as 2.0.0 for "1.x || 3.x", is not outside c.
+func Outside(v *Version, c *Constraint) bool {
+	return GreaterThanRange(v, c) || LessThanRange(v, c)
+}
+
+// Intersects returns true if some version satisfies both a and b.
+func Intersects(a, b *Constraint) bool {
+	for _, i := range a.intervals() {
+		for _, o := range b.intervals() {
+			if i.intersect(o).first() != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
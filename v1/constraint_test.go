@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package semver_test
+
+import (
+	"encoding"
+	"flag"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		input    string
+		matching []string
+		failing  []string
+	}{
+		{">=1.2.3 <2.0.0", []string{"1.2.3", "1.9.9"}, []string{"1.2.2", "2.0.0"}},
+		{"1.x || >=3.0.0-rc.1", []string{"1.0.0", "3.0.0-rc.1", "4.0.0"}, []string{"2.0.0", "3.0.0-beta"}},
+		{"!=1.x", []string{"0.9.0", "2.0.0"}, []string{"1.5.0"}},
+		{"", []string{"0.0.0-0", "1.2.3"}, nil},
+	}
+
+	Convey("Test parsing constraints", t, func() {
+		for _, tc := range tests {
+			Convey(tc.input, func() {
+				c, err := semver.ParseConstraint(tc.input)
+				So(err, ShouldBeNil)
+				So(c.String(), ShouldEqual, tc.input)
+				rf := c.Range()
+				for _, v := range tc.matching {
+					So(rf(semver.New(v)), ShouldBeTrue)
+				}
+				for _, v := range tc.failing {
+					So(rf(semver.New(v)), ShouldBeFalse)
+				}
+			})
+		}
+
+		Convey("Invalid constraints are rejected", func() {
+			_, err := semver.ParseConstraint(">=1.2.3 ||")
+			So(err, ShouldNotBeNil)
+			So(func() { semver.MustParseConstraint("1.2.3.4") }, ShouldPanic)
+		})
+
+		Convey("The zero Constraint matches any version", func() {
+			var c semver.Constraint
+			So(c.Range()(semver.New("1.2.3")), ShouldBeTrue)
+			So(c.String(), ShouldEqual, "")
+		})
+	})
+}
+
+func TestMinMaxVersion(t *testing.T) {
+	tests := []struct {
+		constraint string
+		min        string
+		max        string
+		inclusive  bool
+	}{
+		{">=1.2.3 <2.0.0", "1.2.3", "2.0.0", false},
+		{">1.2.3 <=2.0.0", "1.2.4-0", "2.0.0", true},
+		{">1.2.3-beta", "1.2.3-beta.0", "", false},
+		{"1.x || 3.x", "1.0.0", "4.0.0-0", false},
+		{"<1.0.0 || 2.0.0", "0.0.0-0", "2.0.0", true},
+		{"*", "0.0.0-0", "", false},
+		{"!=0.0.0-0 <1.0.0", "0.0.0-0.0", "1.0.0", false},
+		{">=2.0.0 <1.0.0", "", "", false},
+		{">1.2.3 <1.2.4-0", "", "1.2.4-0", false},
+		{">1.0.18446744073709551615", "1.1.0-0", "", false},
+		{">18446744073709551615.18446744073709551615.18446744073709551615", "", "", false},
+	}
+
+	Convey("Test min and max versions", t, func() {
+		for _, tc := range tests {
+			Convey(tc.constraint, func() {
+				c := semver.MustParseConstraint(tc.constraint)
+				if tc.min == "" {
+					So(semver.MinVersion(c), ShouldBeNil)
+				} else {
+					So(semver.MinVersion(c), ShouldResemble, semver.New(tc.min))
+				}
+				max, inclusive := semver.MaxVersion(c)
+				if tc.max == "" {
+					So(max, ShouldBeNil)
+				} else {
+					So(max, ShouldResemble, semver.New(tc.max))
+				}
+				So(inclusive, ShouldEqual, tc.inclusive)
+			})
+		}
+	})
+}
+
+func TestOutside(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		greater    bool
+		less       bool
+	}{
+		{">=1.2.3 <2.0.0", "1.5.0", false, false},
+		{">=1.2.3 <2.0.0", "2.0.0", true, false},
+		{">=1.2.3 <2.0.0", "1.2.3-rc.1", false, true},
+		{">1.2.3 <=2.0.0", "1.2.3", false, true},
+		{">1.2.3 <=2.0.0", "2.0.0", false, false},
+		{"1.x || 3.x", "2.0.0", false, false},
+		{"1.x || 3.x", "4.0.0", true, false},
+		{"1.x || 3.x", "0.9.0", false, true},
+		{">=1.0.0", "99.0.0", false, false},
+		{"*", "0.0.0-0", false, false},
+		{">=2.0.0 <1.0.0", "1.5.0", false, false},
+	}
+
+	Convey("Test versions outside of ranges", t, func() {
+		for _, tc := range tests {
+			Convey(tc.constraint+" "+tc.version, func() {
+				c, v := semver.MustParseConstraint(tc.constraint), semver.New(tc.version)
+				So(semver.GreaterThanRange(v, c), ShouldEqual, tc.greater)
+				So(semver.LessThanRange(v, c), ShouldEqual, tc.less)
+				So(semver.Outside(v, c), ShouldEqual, tc.greater || tc.less)
+			})
+		}
+	})
+}
+
+func TestIntersects(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		intersects bool
+	}{
+		{">=1.2.0 <2.0.0", ">=1.9.0", true},
+		{">=1.2.0 <2.0.0", ">=2.0.0", false},
+		{">=1.2.0 <=2.0.0", ">=2.0.0", true},
+		{"1.x || 3.x", "2.x", false},
+		{"1.x || 3.x", ">2.5.0 <3.0.1", true},
+		{">1.2.3", "<1.2.4-0", false},
+		{"*", "1.2.3", true},
+		{">=2.0.0 <1.0.0", "*", false},
+		{">1.0.18446744073709551615", ">=1.1.0 <1.2.0", true},
+		{">1.0.18446744073709551615", "<1.1.0-0", false},
+		{">18446744073709551615.18446744073709551615.18446744073709551615", "*", false},
+	}
+
+	Convey("Test intersecting ranges", t, func() {
+		for _, tc := range tests {
+			Convey(tc.a+" and "+tc.b, func() {
+				a, b := semver.MustParseConstraint(tc.a), semver.MustParseConstraint(tc.b)
+				So(semver.Intersects(a, b), ShouldEqual, tc.intersects)
+				So(semver.Intersects(b, a), ShouldEqual, tc.intersects)
+			})
+		}
+	})
+}
+
+func TestConstraintText(t *testing.T) {
+	Convey("Test constraint text", t, func() {
+		var c semver.Constraint
+		var _ encoding.TextUnmarshaler = &c
+		var _ flag.Value = &c
+
+		So(c.UnmarshalText([]byte("~>1.2")), ShouldNotBeNil)
+		So(c.UnmarshalText([]byte("1.2 || >=2.1")), ShouldBeNil)
+		text, err := c.MarshalText()
+		So(err, ShouldBeNil)
+		So(string(text), ShouldEqual, "1.2 || >=2.1")
+		So(c.Range()(semver.New("2.0.0")), ShouldBeFalse)
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(&c, "constraint", "")
+		So(fs.Parse([]string{"-constraint", ">=3"}), ShouldBeNil)
+		So(c.Range()(semver.New("3.0.0")), ShouldBeTrue)
+	})
+}
+
+func TestFilterVersions(t *testing.T) {
+	var catalog semver.Versions
+	for _, s := range []string{
+		"0.9.0", "1.0.0-rc.1", "1.0.0", "1.2.3", "1.9.9", "2.0.0", "3.0.0-beta", "3.0.0", "3.0.3", "3.1.0", "4.0.0", "5.0.0",
+	} {
+		catalog = append(catalog, semver.New(s))
+	}
+
+	tests := []struct {
+		constraint string
+		expected   []string
+	}{
+		{">=1.0.0 <2.0.0", []string{"1.0.0", "1.2.3", "1.9.9"}},
+		{">=1.0.0 <2.0.0 || >=3.0.0 <4.0.0 !=3.0.3 || >=5.0.0", []string{"1.0.0", "1.2.3", "1.9.9", "3.0.0", "3.1.0", "5.0.0"}},
+		{"<1.0.0 || 3.x", []string{"0.9.0", "1.0.0-rc.1", "3.0.0", "3.0.3", "3.1.0"}},
+		{"*", []string{"0.9.0", "1.0.0-rc.1", "1.0.0", "1.2.3", "1.9.9", "2.0.0", "3.0.0-beta", "3.0.0", "3.0.3", "3.1.0", "4.0.0", "5.0.0"}},
+		{">5.0.0", []string{}},
+	}
+
+	Convey("Test filtering versions", t, func() {
+		for _, tc := range tests {
+			Convey(tc.constraint, func() {
+				c := semver.MustParseConstraint(tc.constraint)
+				filtered := c.FilterVersions(catalog)
+				actual := []string{}
+				for _, v := range filtered {
+					actual = append(actual, v.String())
+					So(c.Range()(v), ShouldBeTrue)
+				}
+				So(actual, ShouldResemble, tc.expected)
+			})
+		}
+	})
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	debianUpstreamChars = alphanum + ".+~-:"
+	debianRevisionChars = alphanum + ".+~"
+)
+
+// DebianVersion represents a Debian package version of the form
+// [epoch:]upstream_version[-debian_revision].
+type DebianVersion struct {
+	Epoch    uint64
+	Upstream string
+	Revision string
+}
+
+// NewDebianVersion parses s to create an instance of DebianVersion.
+// It will return an error if s is not a valid Debian version.
+func NewDebianVersion(s string) (*DebianVersion, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil, errors.New("version string empty")
+	}
+
+	d := &DebianVersion{}
+	if i := strings.IndexRune(s, ':'); i != -1 {
+		if !containsOnly(s[:i], numbers) || i == 0 {
+			return nil, fmt.Errorf("epoch must be a number in %q", s)
+		}
+		epoch, err := strconv.ParseUint(s[:i], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		d.Epoch = epoch
+		s = s[i+1:]
+	}
+
+	if i := strings.LastIndex(s, "-"); i != -1 {
+		d.Revision = s[i+1:]
+		s = s[:i]
+		if len(d.Revision) == 0 {
+			return nil, errors.New("revision is empty")
+		}
+		if !containsOnly(d.Revision, debianRevisionChars) {
+			return nil, fmt.Errorf("invalid character found in revision %q", d.Revision)
+		}
+	}
+
+	if len(s) == 0 {
+		return nil, errors.New("upstream version is empty")
+	}
+	if !strings.ContainsAny(s[:1], numbers) {
+		return nil, fmt.Errorf("upstream version must start with a digit %q", s)
+	}
+	if !containsOnly(s, debianUpstreamChars) {
+		return nil, fmt.Errorf("invalid character found in upstream version %q", s)
+	}
+	d.Upstream = s
+
+	return d, nil
+}
+
+// MustDebian is a helper for wrapping NewDebianVersion and will panic if err is not nil.
+func MustDebian(d *DebianVersion, err error) *DebianVersion {
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Compare tests if d is less than, equal to, or greater than o using the
+// dpkg ordering, returning -1, 0, or +1 respectively.
+func (d *DebianVersion) Compare(o *DebianVersion) int {
+	if c := compareUint(d.Epoch, o.Epoch); c != 0 {
+		return c
+	}
+	if c := debianVerRevCmp(d.Upstream, o.Upstream); c != 0 {
+		return c
+	}
+	return debianVerRevCmp(d.Revision, o.Revision)
+}
+
+func (d *DebianVersion) String() string {
+	b := make([]byte, 0, len(d.Upstream)+len(d.Revision)+4)
+	if d.Epoch != 0 {
+		b = strconv.AppendUint(b, d.Epoch, 10)
+		b = append(b, ':')
+	}
+	b = append(b, d.Upstream...)
+	if len(d.Revision) > 0 {
+		b = append(b, '-')
+		b = append(b, d.Revision...)
+	}
+	return string(b)
+}
+
+// debianOrder weights a character for comparison: '~' sorts before the end
+// of the string, letters sort before any other non-digit.
+func debianOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case c >= '0' && c <= '9':
+		return 0
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+// debianVerRevCmp compares upstream versions or revisions as dpkg does,
+// alternating between non-digit and digit parts.
+func debianVerRevCmp(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			if c := compareInt(debianOrder(a, i), debianOrder(b, j)); c != 0 {
+				return c
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = compareInt(int(a[i]), int(b[j]))
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+	return 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// DebianVersions is an array of DebianVersion pointers for sorting.
+type DebianVersions []*DebianVersion
+
+func (s DebianVersions) Len() int {
+	return len(s)
+}
+
+func (s DebianVersions) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s DebianVersions) Less(i, j int) bool {
+	return s[i].Compare(s[j]) < 0
+}
+
+// DebianRange represents a range of Debian versions.
+type DebianRange func(*DebianVersion) bool
+
+// OR combines the existing DebianRange with another DebianRange using logical OR.
+func (rf DebianRange) OR(f DebianRange) DebianRange {
+	return func(d *DebianVersion) bool {
+		return rf(d) || f(d)
+	}
+}
+
+// AND combines the existing DebianRange with another DebianRange using logical AND.
+func (rf DebianRange) AND(f DebianRange) DebianRange {
+	return func(d *DebianVersion) bool {
+		return rf(d) && f(d)
+	}
+}
+
+// ParseDebianRange parses a range of Debian versions and returns a DebianRange.
+// The syntax is that of ParseRange, without wildcards.  The dpkg strict
+// operators "<<" and ">>" are accepted as well:
+//   - ">= 1:2.3-1 << 1:2.4"
+//   - "<< 2.0~rc1 || >= 3.0"
+func ParseDebianRange(s string) (DebianRange, error) {
+	terms, err := splitRangeTerms(s, parseDebianOperator)
+	if err != nil {
+		return nil, err
+	}
+	var orFn DebianRange
+	for _, andTerms := range terms {
+		var andFn DebianRange
+		for _, term := range andTerms {
+			d, err := NewDebianVersion(term.version)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse Debian range %q: %s", s, err)
+			}
+			cmp := term.cmp
+			rf := DebianRange(func(v *DebianVersion) bool {
+				return cmp(v.Compare(d))
+			})
+			if andFn == nil {
+				andFn = rf
+			} else {
+				andFn = andFn.AND(rf)
+			}
+		}
+		if orFn == nil {
+			orFn = andFn
+		} else {
+			orFn = orFn.OR(andFn)
+		}
+	}
+	return orFn, nil
+}
+
+func parseDebianOperator(s string) comparison {
+	switch s {
+	case "<<":
+		return cmpLT
+	case ">>":
+		return cmpGT
+	}
+	return parseComparison(s)
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func newDebian(s string) *semver.DebianVersion {
+	return semver.MustDebian(semver.NewDebianVersion(s))
+}
+
+func TestNewDebianVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *semver.DebianVersion
+	}{
+		{"1.0", &semver.DebianVersion{Upstream: "1.0"}},
+		{"1:2.30-1ubuntu3", &semver.DebianVersion{Epoch: 1, Upstream: "2.30", Revision: "1ubuntu3"}},
+		{"2.4.52-1~deb11u2", &semver.DebianVersion{Upstream: "2.4.52", Revision: "1~deb11u2"}},
+		{"1.2-3-4", &semver.DebianVersion{Upstream: "1.2-3", Revision: "4"}},
+		{"1:2:3-4", &semver.DebianVersion{Epoch: 1, Upstream: "2:3", Revision: "4"}},
+		{"", nil},
+		{"a1.0", nil},
+		{"x:1.0", nil},
+		{"1.0-", nil},
+		{"-1", nil},
+		{"1.0_1", nil},
+		{"1.0-1:2", nil},
+	}
+
+	Convey("Test Debian version parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.input, func() {
+				d, err := semver.NewDebianVersion(tc.input)
+				if tc.expected == nil {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(d, ShouldResemble, tc.expected)
+					So(d.String(), ShouldEqual, tc.input)
+				}
+			})
+		}
+	})
+}
+
+func TestDebianCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0-0", 0},
+		{"0:1.0", "1.0", 0},
+		{"1.0", "1.00", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~~", "1.0~~a", -1},
+		{"1.0~~a", "1.0~", -1},
+		{"1.0~", "1.0", -1},
+		{"1.0", "1.0a", -1},
+		{"1.0a", "1.0+dfsg", -1},
+		{"1.0a", "1.0.1", -1},
+		{"1.0", "1.0-1", -1},
+		{"1.0-1", "1.0-1ubuntu1", -1},
+		{"1.0-9", "1.0-10", -1},
+		{"2.0", "1:0.1", -1},
+		{"1.2.3-1~deb10u1", "1.2.3-1", -1},
+		{"1.10", "1.9", 1},
+	}
+
+	Convey("Test Debian ordering", t, func() {
+		for _, tc := range tests {
+			Convey(tc.a+" "+tc.b, func() {
+				So(newDebian(tc.a).Compare(newDebian(tc.b)), ShouldEqual, tc.expected)
+				So(newDebian(tc.b).Compare(newDebian(tc.a)), ShouldEqual, -tc.expected)
+			})
+		}
+	})
+
+	Convey("Test Debian sort", t, func() {
+		data := semver.DebianVersions{newDebian("1:0.9"), newDebian("1.0"), newDebian("1.0~beta1"), newDebian("1.0-1")}
+		sort.Sort(data)
+		So(data, ShouldResemble, semver.DebianVersions{
+			newDebian("1.0~beta1"), newDebian("1.0"), newDebian("1.0-1"), newDebian("1:0.9"),
+		})
+	})
+}
+
+func TestParseDebianRange(t *testing.T) {
+	type test struct {
+		v        string
+		expected bool
+	}
+	tests := []struct {
+		conditional string
+		data        []test
+	}{
+		{">= 2.4.52-1 << 2.4.56-1", []test{
+			{"2.4.52-1~deb11u2", false},
+			{"2.4.52-1", true},
+			{"2.4.55-1", true},
+			{"2.4.56-1~deb11u1", true},
+			{"2.4.56-1", false},
+		}},
+		{"<< 1:1.0 || = 2:0.1", []test{
+			{"5.0", true},
+			{"1:0.9", true},
+			{"1:1.0", false},
+			{"2:0.1", true},
+		}},
+		{">>1.0~rc1 !=1.0", []test{
+			{"1.0~rc1", false},
+			{"1.0~rc2", true},
+			{"1.0", false},
+		}},
+		{">>> 1.0", nil},
+		{">= a1.0", nil},
+		{"", nil},
+		{">= 1.0 | << 2.0", nil},
+		{">= 1.0 ||", nil},
+	}
+
+	Convey("Test Debian range parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.conditional, func() {
+				r, err := semver.ParseDebianRange(tc.conditional)
+				if tc.data == nil {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					for _, td := range tc.data {
+						Convey(td.v, func() {
+							So(r(newDebian(td.v)), ShouldEqual, td.expected)
+						})
+					}
+				}
+			})
+		}
+	})
+}
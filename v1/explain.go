@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation describes why a version does or does not satisfy a Constraint.
+type Explanation struct {
+	Version    *Version `json:"version"`
+	Constraint string   `json:"constraint"`
+	Satisfied  bool     `json:"satisfied"`
+	// Branches are the OR'ed branches of the constraint, with wildcards and
+	// partial versions expanded.
+	Branches []BranchExplanation `json:"branches"`
+	// Suggestion is the nearest version that satisfies the constraint, if
+	// the version does not and one is known.
+	Suggestion *Version `json:"suggestion,omitempty"`
+}
+
+// BranchExplanation lists the comparators of a branch of a Constraint that
+// a version fails, which is none if the version satisfies the branch.
+type BranchExplanation struct {
+	Branch   string              `json:"branch"`
+	Failures []ComparatorFailure `json:"failures,omitempty"`
+}
+
+// ComparatorFailure is a comparator that a version fails and the reason why,
+// such as "2.1.0 is not < 2.0.0".
+type ComparatorFailure struct {
+	Comparator string `json:"comparator"`
+	Reason     string `json:"reason"`
+}
+
+// Explain returns an Explanation of whether v satisfies c.  If it does not,
+// the Explanation holds the failed comparators of every branch of c and the
+// suggested version, which is the lowest satisfying bound of c above v or
+// else the highest one below v.
+func (c *Constraint) Explain(v *Version) *Explanation {
+	e := &Explanation{Version: v, Constraint: c.String()}
+	for _, g := range c.branches() {
+		b := BranchExplanation{Branch: formatBranch(g)}
+		for _, vr := range g {
+			if !vr.c(v, vr.v) {
+				b.Failures = append(b.Failures, ComparatorFailure{Comparator: vr.String(), Reason: vr.reason(v)})
+			}
+		}
+		e.Satisfied = e.Satisfied || len(b.Failures) == 0
+		e.Branches = append(e.Branches, b)
+	}
+	if !e.Satisfied {
+		e.Suggestion = c.suggest(v)
+	}
+	return e
+}
+
+// String returns the explanation on several lines, such as:
+//
+//	2.1.0 does not satisfy ">=1.0.0 <2.0.0 || 3.x":
+//	  >=1.0.0 <2.0.0: 2.1.0 is not < 2.0.0
+//	  >=3.0.0 <4.0.0: 2.1.0 is not >= 3.0.0
+//	  suggested version: 3.0.0
+func (e *Explanation) String() string {
+	if e.Satisfied {
+		return fmt.Sprintf("%s satisfies %q", e.Version, e.Constraint)
+	}
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%s does not satisfy %q:", e.Version, e.Constraint)
+	for _, b := range e.Branches {
+		reasons := make([]string, len(b.Failures))
+		for i, f := range b.Failures {
+			reasons[i] = f.Reason
+		}
+		_, _ = fmt.Fprintf(&sb, "\n  %s: %s", b.Branch, strings.Join(reasons, ", "))
+	}
+	if e.Suggestion != nil {
+		_, _ = fmt.Fprintf(&sb, "\n  suggested version: %s", e.Suggestion)
+	}
+	return sb.String()
+}
+
+// ConstraintError is returned by Constraint.Verify with the Explanation of
+// why a version does not satisfy a Constraint.
+type ConstraintError struct {
+	*Explanation
+}
+
+func (e *ConstraintError) Error() string {
+	return e.Explanation.String()
+}
+
+// Verify returns a *ConstraintError if v does not satisfy c, and nil otherwise.
+func (c *Constraint) Verify(v *Version) error {
+	if e := c.Explain(v); !e.Satisfied {
+		return &ConstraintError{Explanation: e}
+	}
+	return nil
+}
+
+// reason returns why v fails vr.
+func (vr *versionRange) reason(v *Version) string {
+	switch vr.op {
+	case "=":
+		return fmt.Sprintf("%s is not %s", v, vr.v)
+	case "!=":
+		return fmt.Sprintf("%s is excluded", v)
+	}
+	return fmt.Sprintf("%s is not %s %s", v, vr.op, vr.v)
+}
+
+// suggest returns the satisfying bound of c nearest to v, preferring the
+// lowest one above v, or nil if none is known.
+func (c *Constraint) suggest(v *Version) *Version {
+	var above, below *Version
+	for _, g := range c.branches() {
+		for _, vr := range g {
+			candidate := vr.candidate()
+			if candidate == nil || !satisfiesAll(g, candidate) {
+				continue
+			}
+			if candidate.GT(v) && (above == nil || candidate.LT(above)) {
+				above = candidate
+			}
+			if candidate.LT(v) && (below == nil || candidate.GT(below)) {
+				below = candidate
+			}
+		}
+	}
+	if above != nil {
+		return above
+	}
+	return below
+}
+
+// candidate returns the version nearest to the bound of vr that satisfies
+// vr, or nil if there is none or it is not known.  The version below an
+// exclusive upper bound is only known if the bound has a patch version.
+func (vr *versionRange) candidate() *Version {
+	if vr.upper != nil {
+		// The release rather than the pre-release of the upper bound.
+		return successor(vr.upper)
+	}
+	switch vr.op {
+	case "=", ">=", "<=":
+		return vr.v.Clone()
+	case ">", "!=":
+		return successor(vr.v)
+	case "<":
+		if vr.v.IsPreRelease() || vr.v.Patch == 0 {
+			return nil
+		}
+		return &Version{Major: vr.v.Major, Minor: vr.v.Minor, Patch: vr.v.Patch - 1, PreRelease: Identifiers{}, Metadata: Identifiers{}}
+	}
+	return nil
+}
+
+// successor returns the release that follows v: the release of a
+// pre-release, and the next release otherwise, or nil if there is none.
+func successor(v *Version) *Version {
+	if v.IsPreRelease() {
+		return &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, PreRelease: Identifiers{}, Metadata: Identifiers{}}
+	}
+	return nextRelease(v)
+}
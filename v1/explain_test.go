@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package semver_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		satisfied  bool
+		failures   [][]string
+		suggestion string
+	}{
+		{">=1.0.0 <2.0.0", "1.5.0", true, [][]string{nil}, ""},
+		{"<2.0.0", "2.1.0", false, [][]string{{"2.1.0 is not < 2.0.0"}}, ""},
+		{"<2.0.3", "2.1.0", false, [][]string{{"2.1.0 is not < 2.0.3"}}, "2.0.2"},
+		{">=1.0.0 <2.0.0 || 3.x", "2.1.0", false, [][]string{
+			{"2.1.0 is not < 2.0.0"},
+			{"2.1.0 is not >= 3.0.0"},
+		}, "3.0.0"},
+		{">1.2.3", "1.0.0", false, [][]string{{"1.0.0 is not > 1.2.3"}}, "1.2.4"},
+		{">1.2.3-rc.1", "1.2.3-beta.1", false, [][]string{{"1.2.3-beta.1 is not > 1.2.3-rc.1"}}, "1.2.3"},
+		{"1.2.3", "1.2.4", false, [][]string{{"1.2.4 is not 1.2.3"}}, "1.2.3"},
+		{"!=1.2.3", "1.2.3", false, [][]string{{"1.2.3 is excluded"}}, "1.2.4"},
+		{"!=1.x", "1.5.0", false, [][]string{{"1.5.0 is excluded"}}, "2.0.0"},
+		{"1.x", "2.0.0-rc.1", false, [][]string{{"2.0.0-rc.1 is not < 2.0.0-0"}}, "1.0.0"},
+		{">=1.0.0 <=1.4.0 !1.4.0", "1.4.0", false, [][]string{{"1.4.0 is excluded"}}, "1.0.0"},
+		{">=1.0.0 <1.0.0", "1.0.0", false, [][]string{{"1.0.0 is not < 1.0.0"}}, ""},
+		{">=2.0.0 <1.0.0", "1.5.0", false, [][]string{{"1.5.0 is not >= 2.0.0", "1.5.0 is not < 1.0.0"}}, ""},
+	}
+
+	Convey("Test explaining constraints", t, func() {
+		for _, tc := range tests {
+			Convey(tc.constraint+" "+tc.version, func() {
+				e := semver.MustParseConstraint(tc.constraint).Explain(semver.New(tc.version))
+				So(e.Satisfied, ShouldEqual, tc.satisfied)
+				So(e.Branches, ShouldHaveLength, len(tc.failures))
+				for i, b := range e.Branches {
+					var reasons []string
+					for _, f := range b.Failures {
+						reasons = append(reasons, f.Reason)
+					}
+					So(reasons, ShouldResemble, tc.failures[i])
+				}
+				if tc.suggestion == "" {
+					So(e.Suggestion, ShouldBeNil)
+				} else {
+					So(e.Suggestion, ShouldResemble, semver.New(tc.suggestion))
+				}
+			})
+		}
+	})
+}
+
+func TestExplanationString(t *testing.T) {
+	Convey("Test explanation text", t, func() {
+		c := semver.MustParseConstraint(">=1.0.0 <2.0.0 || 3.x")
+
+		So(c.Explain(semver.New("1.2.0")).String(), ShouldEqual, `1.2.0 satisfies ">=1.0.0 <2.0.0 || 3.x"`)
+		So(c.Explain(semver.New("2.1.0")).String(), ShouldEqual, `2.1.0 does not satisfy ">=1.0.0 <2.0.0 || 3.x":
+  >=1.0.0 <2.0.0: 2.1.0 is not < 2.0.0
+  >=3.0.0 <4.0.0-0: 2.1.0 is not >= 3.0.0
+  suggested version: 3.0.0`)
+	})
+
+	Convey("Test explanation JSON", t, func() {
+		e := semver.MustParseConstraint("1.2.3").Explain(semver.New("1.2.4"))
+		data, err := json.Marshal(e)
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `{"version":"1.2.4","constraint":"1.2.3","satisfied":false,`+
+			`"branches":[{"branch":"=1.2.3","failures":[{"comparator":"=1.2.3","reason":"1.2.4 is not 1.2.3"}]}],`+
+			`"suggestion":"1.2.3"}`)
+	})
+}
+
+func TestConstraintVerify(t *testing.T) {
+	Convey("Test verifying versions", t, func() {
+		c := semver.MustParseConstraint(">=1.2.3 <1.3.0")
+
+		So(c.Verify(semver.New("1.2.5")), ShouldBeNil)
+
+		err := c.Verify(semver.New("1.3.0"))
+		So(err, ShouldNotBeNil)
+		var ce *semver.ConstraintError
+		So(errors.As(err, &ce), ShouldBeTrue)
+		So(ce.Suggestion, ShouldResemble, semver.New("1.2.3"))
+		So(err.Error(), ShouldEqual, ce.Explanation.String())
+	})
+}
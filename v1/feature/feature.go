@@ -0,0 +1,242 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+/*
+Package feature gates features by version ranges, replacing checks such as
+"if clientVersion.GE(x)" that are scattered around a code base with a
+declarative table.
+
+Each gate maps a feature to the range, in the syntax of semver.ParseRange, of
+the versions that have it, and may schedule its deprecation:
+
+	[
+	  {"name": "streaming", "range": ">=1.4.0"},
+	  {"name": "legacy-auth", "range": "<3.0.0", "deprecated": "2.5.0",
+	   "message": "use token authentication"}
+	]
+
+	table, err := feature.Load(data)
+	if err != nil {
+		return err
+	}
+	if table.Enabled("streaming", clientVersion) {
+		...
+	}
+	for _, w := range table.Warnings(clientVersion) {
+		log.Print(w)
+	}
+
+A feature may have several gates, for example if it was removed and later
+reintroduced, but their ranges must not overlap.  Gates are validated when
+the table is created.
+
+Gates may also be loaded from YAML.  Gate has yaml tags and its range and
+deprecation version implement encoding.TextUnmarshaler, so a []Gate can be
+decoded with a YAML library that honors it, such as gopkg.in/yaml.v2, and
+passed to New:
+
+	var gates []feature.Gate
+	if err := yaml.Unmarshal(data, &gates); err != nil {
+		return err
+	}
+	table, err := feature.New(gates...)
+*/
+package feature // import "l7e.io/semver/v1/feature"
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"l7e.io/semver/v1"
+)
+
+// Gate declares the versions that have a feature.
+type Gate struct {
+	// Name is the name of the feature.
+	Name string `json:"name" yaml:"name"`
+	// Range is the range of the versions that have the feature.
+	Range semver.Constraint `json:"range" yaml:"range"`
+	// Deprecated is the first version for which the feature is deprecated,
+	// if any.  It must satisfy Range.
+	Deprecated *semver.Version `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	// Message tells users of a deprecated feature what to do instead.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// Deprecation is a scheduled deprecation of a feature.
+type Deprecation struct {
+	// Feature is the name of the deprecated feature.
+	Feature string `json:"feature"`
+	// Since is the first version for which the feature is deprecated.
+	Since *semver.Version `json:"since"`
+	// RemovedIn is the first version above Since that no longer has the
+	// feature, or nil if its removal is not scheduled.  It is a release
+	// rather than its lowest pre-release, so that the removal of "2.x" is
+	// reported as 3.0.0 and not 3.0.0-0.
+	RemovedIn *semver.Version `json:"removedIn,omitempty"`
+	// Message tells users of the feature what to do instead.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// String returns a warning about the deprecation.
+func (d Deprecation) String() string {
+	s := fmt.Sprintf("feature %q is deprecated since %s", d.Feature, d.Since)
+	if d.RemovedIn != nil {
+		s += fmt.Sprintf(" and removed in %s", d.RemovedIn)
+	}
+	if d.Message != "" {
+		s += ": " + d.Message
+	}
+	return s
+}
+
+// Table is a validated table of feature gates.  It is safe for concurrent use.
+type Table struct {
+	gates        map[string][]gate
+	names        []string
+	deprecations []scheduled
+}
+
+// gate is the range of a Gate, parsed and compiled once when the table is
+// created rather than on every lookup.
+type gate struct {
+	c  *semver.Constraint
+	rf semver.Range
+}
+
+// scheduled is a deprecation and the range of the gate that it deprecates.
+type scheduled struct {
+	Deprecation
+	rf semver.Range
+}
+
+// Load parses a table from a JSON array of gates.
+func Load(data []byte) (*Table, error) {
+	var gates []Gate
+	if err := json.Unmarshal(data, &gates); err != nil {
+		return nil, err
+	}
+	return New(gates...)
+}
+
+// New returns a table of the given gates.  It returns an error if a gate has
+// no name, a range that no version satisfies, a range that overlaps another
+// gate of the same feature, or a deprecation version outside its range.
+func New(gates ...Gate) (*Table, error) {
+	t := &Table{gates: map[string][]gate{}}
+	for i := range gates {
+		g := &gates[i]
+		if g.Name == "" {
+			return nil, fmt.Errorf("gate %d has no name", i)
+		}
+		if g.Range.String() == "" {
+			return nil, fmt.Errorf("feature %q has no range", g.Name)
+		}
+		c, err := semver.ParseConstraint(g.Range.String())
+		if err != nil {
+			return nil, fmt.Errorf("feature %q: %w", g.Name, err)
+		}
+		if semver.MinVersion(c) == nil {
+			return nil, fmt.Errorf("feature %q: no version satisfies %q", g.Name, c)
+		}
+		for _, o := range t.gates[g.Name] {
+			if semver.Intersects(c, o.c) {
+				return nil, fmt.Errorf("feature %q: range %q overlaps %q", g.Name, c, o.c)
+			}
+		}
+		rf := c.Range()
+		if g.Deprecated != nil {
+			d, err := deprecation(g, c, rf)
+			if err != nil {
+				return nil, err
+			}
+			t.deprecations = append(t.deprecations, scheduled{d, rf})
+		}
+		if _, ok := t.gates[g.Name]; !ok {
+			t.names = append(t.names, g.Name)
+		}
+		t.gates[g.Name] = append(t.gates[g.Name], gate{c, rf})
+	}
+
+	sort.Strings(t.names)
+	sort.SliceStable(t.deprecations, func(i, j int) bool {
+		if c := t.deprecations[i].Since.Compare(t.deprecations[j].Since); c != 0 {
+			return c < 0
+		}
+		return t.deprecations[i].Feature < t.deprecations[j].Feature
+	})
+	return t, nil
+}
+
+// deprecation returns the deprecation of g, which is removed at the
+// exclusive upper bound of its range c, compiled to rf, if it has one.
+func deprecation(g *Gate, c *semver.Constraint, rf semver.Range) (Deprecation, error) {
+	if !rf(g.Deprecated) {
+		return Deprecation{}, fmt.Errorf("feature %q: deprecation version %s does not satisfy %q", g.Name, g.Deprecated, c)
+	}
+	d := Deprecation{Feature: g.Name, Since: g.Deprecated.Clone(), Message: g.Message}
+	if upper, inclusive := semver.MaxVersion(c); upper != nil && !inclusive {
+		if len(upper.PreRelease) == 1 && upper.PreRelease[0] == (semver.Identifier{Num: 0, IsNum: true}) {
+			upper.PreRelease = semver.Identifiers{}
+		}
+		d.RemovedIn = upper
+	}
+	return d, nil
+}
+
+// Enabled returns true if v has the feature.  Unknown features are disabled.
+func (t *Table) Enabled(feature string, v *semver.Version) bool {
+	for _, g := range t.gates[feature] {
+		if g.rf(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// FeaturesFor returns the sorted names of the features that v has.
+func (t *Table) FeaturesFor(v *semver.Version) []string {
+	var features []string
+	for _, name := range t.names {
+		if t.Enabled(name, v) {
+			features = append(features, name)
+		}
+	}
+	return features
+}
+
+// Warnings returns the deprecations of the features that v has and that are
+// deprecated for v.
+func (t *Table) Warnings(v *semver.Version) []Deprecation {
+	var warnings []Deprecation
+	for _, d := range t.deprecations {
+		if v.GE(d.Since) && d.rf(v) {
+			warnings = append(warnings, d.Deprecation)
+		}
+	}
+	return warnings
+}
+
+// Schedule returns every deprecation of the table, ordered by the version
+// that they start at.
+func (t *Table) Schedule() []Deprecation {
+	schedule := make([]Deprecation, len(t.deprecations))
+	for i, d := range t.deprecations {
+		schedule[i] = d.Deprecation
+	}
+	return schedule
+}
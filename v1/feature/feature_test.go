@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package feature_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/yaml.v2"
+	"l7e.io/semver/v1"
+	"l7e.io/semver/v1/feature"
+)
+
+const gates = `[
+	{"name": "streaming", "range": ">=1.4.0"},
+	{"name": "legacy-auth", "range": "<3.0.0", "deprecated": "2.5.0", "message": "use token authentication"},
+	{"name": "batch", "range": ">=1.2.0 <2.0.0", "deprecated": "1.8.0"},
+	{"name": "batch", "range": ">=2.3.0"},
+	{"name": "compression", "range": "2.x", "deprecated": "2.1.0"}
+]`
+
+func TestTable(t *testing.T) {
+	table, err := feature.Load([]byte(gates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Test enabled features", t, func() {
+		tests := []struct {
+			version  string
+			features []string
+			warnings []string
+		}{
+			{"1.0.0", []string{"legacy-auth"}, nil},
+			{"1.5.0", []string{"batch", "legacy-auth", "streaming"}, nil},
+			{"1.9.0", []string{"batch", "legacy-auth", "streaming"}, []string{
+				`feature "batch" is deprecated since 1.8.0 and removed in 2.0.0`,
+			}},
+			{"2.0.0", []string{"compression", "legacy-auth", "streaming"}, nil},
+			{"2.6.0", []string{"batch", "compression", "legacy-auth", "streaming"}, []string{
+				`feature "compression" is deprecated since 2.1.0 and removed in 3.0.0`,
+				`feature "legacy-auth" is deprecated since 2.5.0 and removed in 3.0.0: use token authentication`,
+			}},
+			{"3.0.0", []string{"batch", "streaming"}, nil},
+		}
+		for _, tc := range tests {
+			Convey(tc.version, func() {
+				v := semver.New(tc.version)
+				So(table.FeaturesFor(v), ShouldResemble, tc.features)
+				for _, f := range tc.features {
+					So(table.Enabled(f, v), ShouldBeTrue)
+				}
+				var warnings []string
+				for _, w := range table.Warnings(v) {
+					warnings = append(warnings, w.String())
+				}
+				So(warnings, ShouldResemble, tc.warnings)
+			})
+		}
+		So(table.Enabled("unknown", semver.New("1.5.0")), ShouldBeFalse)
+	})
+
+	Convey("Test the deprecation schedule", t, func() {
+		var schedule []string
+		for _, d := range table.Schedule() {
+			schedule = append(schedule, d.Feature+" "+d.Since.String())
+		}
+		So(schedule, ShouldResemble, []string{"batch 1.8.0", "compression 2.1.0", "legacy-auth 2.5.0"})
+		So(table.Schedule()[2].RemovedIn, ShouldResemble, semver.New("3.0.0"))
+	})
+}
+
+const yamlGates = `
+- name: streaming
+  range: ">=1.4.0"
+- name: legacy-auth
+  range: <3.0.0
+  deprecated: 2.5.0
+  message: use token authentication
+- name: batch
+  range: ">=1.2.0 <2.0.0"
+  deprecated: 1.8.0
+- name: batch
+  range: ">=2.3.0"
+- name: compression
+  range: 2.x
+  deprecated: 2.1.0
+`
+
+func TestYAML(t *testing.T) {
+	Convey("Test loading gates from YAML", t, func() {
+		var decoded []feature.Gate
+		So(yaml.Unmarshal([]byte(yamlGates), &decoded), ShouldBeNil)
+		So(decoded, ShouldHaveLength, 5)
+		So(decoded[1].Range.String(), ShouldEqual, "<3.0.0")
+		So(decoded[1].Deprecated.String(), ShouldEqual, "2.5.0")
+
+		table, err := feature.New(decoded...)
+		So(err, ShouldBeNil)
+		expected, err := feature.Load([]byte(gates))
+		So(err, ShouldBeNil)
+		So(table.Schedule(), ShouldResemble, expected.Schedule())
+		for _, s := range []string{"1.0.0", "1.9.0", "2.6.0", "3.0.0"} {
+			v := semver.New(s)
+			So(table.FeaturesFor(v), ShouldResemble, expected.FeaturesFor(v))
+			So(table.Warnings(v), ShouldResemble, expected.Warnings(v))
+		}
+
+		So(yaml.Unmarshal([]byte("- name: streaming\n  range: \">>1.4.0\"\n"), &decoded), ShouldNotBeNil)
+	})
+}
+
+func TestInvalidGates(t *testing.T) {
+	tests := []struct {
+		name  string
+		gates string
+		err   string
+	}{
+		{"no name", `[{"range": "1.x"}]`, "gate 0 has no name"},
+		{"no range", `[{"name": "a"}]`, `feature "a" has no range`},
+		{"invalid range", `[{"name": "a", "range": "~>1.2"}]`, ""},
+		{"empty range", `[{"name": "a", "range": ">=2.0.0 <1.0.0"}]`, `feature "a": no version satisfies ">=2.0.0 <1.0.0"`},
+		{"overlapping ranges", `[{"name": "a", "range": "1.x"}, {"name": "a", "range": ">=1.9.0 <3"}]`, `feature "a": range ">=1.9.0 <3" overlaps "1.x"`},
+		{"deprecation outside range", `[{"name": "a", "range": "1.x", "deprecated": "2.0.0"}]`, `feature "a": deprecation version 2.0.0 does not satisfy "1.x"`},
+		{"invalid deprecation", `[{"name": "a", "range": "1.x", "deprecated": "2.0"}]`, ""},
+		{"not an array", `{"name": "a", "range": "1.x"}`, ""},
+	}
+
+	Convey("Test invalid gates", t, func() {
+		for _, tc := range tests {
+			Convey(tc.name, func() {
+				table, err := feature.Load([]byte(tc.gates))
+				So(table, ShouldBeNil)
+				So(err, ShouldNotBeNil)
+				if tc.err != "" {
+					So(err.Error(), ShouldEqual, tc.err)
+				}
+			})
+		}
+	})
+
+	Convey("Test gates of different features may overlap", t, func() {
+		table, err := feature.New(
+			feature.Gate{Name: "a", Range: *semver.MustParseConstraint("1.x")},
+			feature.Gate{Name: "b", Range: *semver.MustParseConstraint("1.x")},
+		)
+		So(err, ShouldBeNil)
+		So(table.FeaturesFor(semver.New("1.2.0")), ShouldResemble, []string{"a", "b"})
+	})
+}
@@ -0,0 +1,188 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"l7e.io/semver/v1"
+	"l7e.io/semver/v1/semvertest"
+)
+
+// The seeds of the fuzz targets are added here and in testdata/fuzz, which
+// also holds the inputs of the failures the targets have found.
+var fuzzVersions = []string{
+	"0.0.0",
+	"1.2.3",
+	"1.2.3-alpha",
+	"1.2.3-alpha.1",
+	"1.2.3-0.3.7",
+	"1.2.3-x.7.z.92",
+	"1.2.3+build.5",
+	"1.2.3-rc.1+build.5",
+	"1.0.0-alpha-a.b-c-somethinglong+build.1-aef.1-its-okay",
+}
+
+var fuzzRanges = []string{
+	"1.2.3",
+	">=1.2.3 <2.0.0",
+	">= 1.2.3   <=  2.0.0",
+	"<2.0.0 || >=3.0.0",
+	"!1.2.3 !=2.0.0-beta.2",
+	"1.x",
+	"1.2.X",
+	">=1.x <=2.3.x",
+	"!=1.x",
+	"^1.2 || ~2.3.4",
+	"\t>=1.2.3\n<2.0.0",
+	">=1.2.3 || ",
+	"|| 1.2.3",
+	"1.2.3 || || 2.0.0",
+}
+
+func FuzzNewVersion(f *testing.F) {
+	for _, corpus := range [][]string{fuzzVersions, semvertest.Valid, semvertest.Overflow, semvertest.Invalid} {
+		for _, s := range corpus {
+			f.Add(s)
+		}
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			if semvertest.Regexp.MatchString(s) && !errors.Is(err, strconv.ErrRange) {
+				t.Fatalf("NewVersion(%q) rejected a valid version: %s", s, err)
+			}
+			return
+		}
+		if !semvertest.Regexp.MatchString(s) {
+			t.Fatalf("NewVersion(%q) accepted an invalid version", s)
+		}
+		if v.String() != s {
+			t.Fatalf("NewVersion(%q).String() = %q", s, v.String())
+		}
+	})
+}
+
+func FuzzParseRange(f *testing.F) {
+	for _, s := range fuzzRanges {
+		f.Add(s, "1.2.3")
+	}
+	f.Fuzz(func(t *testing.T, s, version string) {
+		c, err := semver.ParseConstraint(s)
+		if err != nil {
+			return
+		}
+		rf := c.Range()
+		versions := semver.Versions{}
+		if v, err := semver.NewVersion(version); err == nil {
+			versions = append(versions, v)
+		}
+		for _, v := range fuzzVersions {
+			versions = append(versions, semver.New(v))
+		}
+		for _, v := range versions {
+			// The compiled intervals of the Range must agree with the
+			// comparators that Explain evaluates.
+			if matched, explained := rf(v), c.Explain(v).Satisfied; matched != explained {
+				t.Fatalf("range %q matches %q: %t, but Explain reports %t", s, v, matched, explained)
+			}
+		}
+	})
+}
+
+func FuzzJSONRoundTrip(f *testing.F) {
+	for _, s := range fuzzVersions {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			return
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("json.Marshal(%q): %s", s, err)
+		}
+		decoded := &semver.Version{}
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("json.Unmarshal(%s): %s", data, err)
+		}
+		if decoded.String() != v.String() {
+			t.Fatalf("JSON round trip of %q returned %q", s, decoded)
+		}
+	})
+}
+
+func FuzzSQLRoundTrip(f *testing.F) {
+	for _, s := range fuzzVersions {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			return
+		}
+		value, err := v.Value()
+		if err != nil {
+			t.Fatalf("Value(%q): %s", s, err)
+		}
+		scanned := &semver.Version{}
+		if err := scanned.Scan(value); err != nil {
+			t.Fatalf("Scan(%v): %s", value, err)
+		}
+		if scanned.String() != v.String() {
+			t.Fatalf("SQL round trip of %q returned %q", s, scanned)
+		}
+		scanned = &semver.Version{}
+		if err := scanned.Scan([]byte(s)); err != nil || scanned.String() != v.String() {
+			t.Fatalf("Scan([]byte(%q)) returned %q, %v", s, scanned, err)
+		}
+	})
+}
+
+func FuzzCompare(f *testing.F) {
+	for i := range fuzzVersions {
+		f.Add(fuzzVersions[i], fuzzVersions[(i+1)%len(fuzzVersions)], fuzzVersions[(i+2)%len(fuzzVersions)])
+	}
+	f.Fuzz(func(t *testing.T, a, b, c string) {
+		va, errA := semver.NewVersion(a)
+		vb, errB := semver.NewVersion(b)
+		vc, errC := semver.NewVersion(c)
+		if errA != nil || errB != nil || errC != nil {
+			return
+		}
+
+		if va.Compare(va) != 0 {
+			t.Fatalf("%q does not equal itself", a)
+		}
+		if ab, ba := va.Compare(vb), vb.Compare(va); ab != -ba {
+			t.Fatalf("Compare is not antisymmetric: %q vs %q is %d, %q vs %q is %d", a, b, ab, b, a, ba)
+		}
+		if ab, bc, ac := va.Compare(vb), vb.Compare(vc), va.Compare(vc); ab <= 0 && bc <= 0 && ac > 0 {
+			t.Fatalf("Compare is not transitive: %q <= %q <= %q but %q > %q", a, b, c, a, c)
+		}
+		if ab, bc, ac := va.Compare(vb), vb.Compare(vc), va.Compare(vc); ab >= 0 && bc >= 0 && ac < 0 {
+			t.Fatalf("Compare is not transitive: %q >= %q >= %q but %q < %q", a, b, c, a, c)
+		}
+	})
+}
@@ -0,0 +1,290 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	gemVersionPattern = regexp.MustCompile(`^[0-9]+(?:\.[0-9a-zA-Z]+)*(?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+	gemSegmentPattern = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+)
+
+// GemVersion represents a RubyGems version.  It has any number of segments,
+// and a version with a letter in any segment is a pre-release.
+type GemVersion struct {
+	original string
+	Segments Identifiers
+}
+
+// NewGemVersion parses s to create an instance of GemVersion.  As in
+// RubyGems, a blank string is version "0" and "-" is a shorthand for ".pre.".
+// It will return an error if s is not a valid RubyGems version.
+func NewGemVersion(s string) (*GemVersion, error) {
+	version := strings.TrimSpace(s)
+	if len(version) == 0 {
+		version = "0"
+	}
+	if !gemVersionPattern.MatchString(version) {
+		return nil, fmt.Errorf("malformed version number string %q", s)
+	}
+	version = strings.Replace(version, "-", ".pre.", -1)
+
+	g := &GemVersion{original: version}
+	for _, str := range gemSegmentPattern.FindAllString(version, -1) {
+		if containsOnly(str, numbers) {
+			n, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			g.Segments = append(g.Segments, Identifier{Num: n, IsNum: true})
+		} else {
+			g.Segments = append(g.Segments, Identifier{Str: str})
+		}
+	}
+
+	return g, nil
+}
+
+// MustGem is a helper for wrapping NewGemVersion and will panic if err is not nil.
+func MustGem(g *GemVersion, err error) *GemVersion {
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// GemFromVersion converts a Version into a GemVersion.  Pre-release
+// identifiers follow a "pre" segment, as RubyGems does for "-", and are split
+// into segments of digits and of letters, dropping hyphens.  Build metadata
+// is dropped.
+func GemFromVersion(v *Version) *GemVersion {
+	segments := Identifiers{{Num: v.Major, IsNum: true}, {Num: v.Minor, IsNum: true}, {Num: v.Patch, IsNum: true}}
+	if v.IsPreRelease() {
+		segments = append(segments, Identifier{Str: "pre"})
+		for _, id := range v.PreRelease {
+			if id.IsNum {
+				segments = append(segments, id)
+				continue
+			}
+			for _, str := range gemSegmentPattern.FindAllString(id.Str, -1) {
+				if n, err := strconv.ParseUint(str, 10, 64); err == nil {
+					segments = append(segments, Identifier{Num: n, IsNum: true})
+				} else {
+					segments = append(segments, Identifier{Str: str})
+				}
+			}
+		}
+	}
+	return &GemVersion{original: segments.String(), Segments: segments}
+}
+
+// IsPreRelease returns true if g contains a letter, false otherwise.
+func (g *GemVersion) IsPreRelease() bool {
+	for _, s := range g.Segments {
+		if !s.IsNum {
+			return true
+		}
+	}
+	return false
+}
+
+// Release returns the release of g, which drops every segment from the
+// first string segment onwards.
+func (g *GemVersion) Release() *GemVersion {
+	r := &GemVersion{}
+	for _, s := range g.Segments {
+		if !s.IsNum {
+			break
+		}
+		r.Segments = append(r.Segments, s)
+	}
+	r.original = r.Segments.String()
+	return r
+}
+
+// Bump returns the next significant release of g, as used by "~>": trailing
+// string segments and the last numeric segment are dropped, and the segment
+// before that is incremented.  Bump of "5.3.1" is "5.4" and of "5" is "6".
+func (g *GemVersion) Bump() *GemVersion {
+	segments := g.Release().Segments
+	if len(segments) > 1 {
+		segments = segments[:len(segments)-1]
+	}
+	segments = segments.Clone()
+	segments[len(segments)-1].Num++
+	return &GemVersion{original: segments.String(), Segments: segments}
+}
+
+// canonicalSegments drops trailing zeroes from both the release and the
+// pre-release parts of g.
+func (g *GemVersion) canonicalSegments() Identifiers {
+	release := g.Release().Segments
+	pre := g.Segments[len(release):]
+	return append(trimGemZeroes(release).Clone(), trimGemZeroes(pre)...)
+}
+
+func trimGemZeroes(ids Identifiers) Identifiers {
+	for len(ids) > 0 && ids[len(ids)-1].IsNum && ids[len(ids)-1].Num == 0 {
+		ids = ids[:len(ids)-1]
+	}
+	return ids
+}
+
+// Compare tests if g is less than, equal to, or greater than o using the
+// RubyGems ordering, returning -1, 0, or +1 respectively.  String segments
+// sort before numeric segments, and missing segments are zero.
+func (g *GemVersion) Compare(o *GemVersion) int {
+	l, r := g.canonicalSegments(), o.canonicalSegments()
+	zero := Identifier{IsNum: true}
+	for i := 0; i < len(l) || i < len(r); i++ {
+		lhs, rhs := zero, zero
+		if i < len(l) {
+			lhs = l[i]
+		}
+		if i < len(r) {
+			rhs = r[i]
+		}
+		if lhs.IsNum != rhs.IsNum {
+			if lhs.IsNum {
+				return 1
+			}
+			return -1
+		}
+		if c := lhs.Compare(rhs); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// String returns the version string g was parsed from, with "-" expanded.
+func (g *GemVersion) String() string {
+	return g.original
+}
+
+// GemVersions is an array of GemVersion pointers for sorting.
+type GemVersions []*GemVersion
+
+func (s GemVersions) Len() int {
+	return len(s)
+}
+
+func (s GemVersions) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s GemVersions) Less(i, j int) bool {
+	return s[i].Compare(s[j]) < 0
+}
+
+// GemConstraint is a single operator and version pair of a GemRequirement.
+type GemConstraint struct {
+	Operator string
+	Version  *GemVersion
+}
+
+// SatisfiedBy checks if g satisfies the constraint.
+func (c GemConstraint) SatisfiedBy(g *GemVersion) bool {
+	switch c.Operator {
+	case "~>":
+		return g.Compare(c.Version) >= 0 && g.Release().Compare(c.Version.Bump()) < 0
+	case "!=":
+		return g.Compare(c.Version) != 0
+	}
+	return parseComparison(c.Operator)(g.Compare(c.Version))
+}
+
+func (c GemConstraint) String() string {
+	return c.Operator + " " + c.Version.String()
+}
+
+// GemRequirement is a list of constraints, all of which must be satisfied,
+// as in Gem::Requirement.
+type GemRequirement []GemConstraint
+
+var gemOperators = []string{"~>", "!=", ">=", "<=", "=", ">", "<"}
+
+// ParseGemRequirement parses a comma separated list of RubyGems constraints,
+// such as "~> 2.2, >= 2.2.1".  A constraint without an operator is "=".  An
+// empty requirement is ">= 0", which any version satisfies, as the default
+// Gem::Requirement.
+func ParseGemRequirement(s string) (GemRequirement, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		s = ">= 0"
+	}
+	var req GemRequirement
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		op := "="
+		for _, candidate := range gemOperators {
+			if strings.HasPrefix(part, candidate) {
+				op = candidate
+				part = part[len(candidate):]
+				break
+			}
+		}
+		if len(strings.TrimSpace(part)) == 0 {
+			return nil, fmt.Errorf("illformed requirement %q", s)
+		}
+		g, err := NewGemVersion(part)
+		if err != nil {
+			return nil, fmt.Errorf("illformed requirement %q: %s", s, err)
+		}
+		req = append(req, GemConstraint{Operator: op, Version: g})
+	}
+	return req, nil
+}
+
+// SatisfiedBy checks if g satisfies every constraint of req.
+func (req GemRequirement) SatisfiedBy(g *GemVersion) bool {
+	for _, c := range req {
+		if !c.SatisfiedBy(g) {
+			return false
+		}
+	}
+	return true
+}
+
+// Range returns a Range that converts each Version with GemFromVersion and
+// checks it against req.
+func (req GemRequirement) Range() Range {
+	return func(v *Version) bool {
+		return req.SatisfiedBy(GemFromVersion(v))
+	}
+}
+
+func (req GemRequirement) String() string {
+	parts := make([]string, len(req))
+	for i, c := range req {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseGemRange parses a RubyGems requirement and returns a Range.
+func ParseGemRange(s string) (Range, error) {
+	req, err := ParseGemRequirement(s)
+	if err != nil {
+		return nil, err
+	}
+	return req.Range(), nil
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func newGem(s string) *semver.GemVersion {
+	return semver.MustGem(semver.NewGemVersion(s))
+}
+
+func TestNewGemVersion(t *testing.T) {
+	tests := []struct {
+		input      string
+		expected   string
+		preRelease bool
+	}{
+		{"1.0", "1.0", false},
+		{"  5.2.4.3 ", "5.2.4.3", false},
+		{"", "0", false},
+		{"1.0.0.pre.3", "1.0.0.pre.3", true},
+		{"1.2.b1", "1.2.b1", true},
+		{"2.0-rc1", "2.0.pre.rc1", true},
+		{"1.0-beta-2", "1.0.pre.beta.pre.2", true},
+		{"junk", "", false},
+		{"1..2", "", false},
+		{"1.0 2", "", false},
+		{"1.0-", "", false},
+		{".1", "", false},
+	}
+
+	Convey("Test RubyGems version parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.input, func() {
+				g, err := semver.NewGemVersion(tc.input)
+				if tc.expected == "" {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(g.String(), ShouldEqual, tc.expected)
+					So(g.IsPreRelease(), ShouldEqual, tc.preRelease)
+				}
+			})
+		}
+	})
+}
+
+func TestGemCompare(t *testing.T) {
+	Convey("Test RubyGems ordering", t, func() {
+		expected := []string{
+			"0.9", "1.0.a.2", "1.0.b1", "1.0.rc", "1.0", "1.0.1", "1.1.pre", "1.1", "1.1.1", "1.10", "2.0.0.rc1", "2",
+		}
+		data := make(semver.GemVersions, len(expected))
+		for i := range expected {
+			data[len(data)-1-i] = newGem(expected[i])
+		}
+
+		sort.Sort(data)
+
+		for i, g := range data {
+			So(g.String(), ShouldEqual, expected[i])
+		}
+	})
+
+	Convey("Test RubyGems equality", t, func() {
+		tests := [][]string{
+			{"1", "1.0", "1.0.0"},
+			{"1.2.b1", "1.2.b.1", "1.2.0.b1"},
+			{"1.0-alpha", "1.0.pre.alpha"},
+		}
+		for _, equal := range tests {
+			Convey(equal[0], func() {
+				for _, s := range equal[1:] {
+					So(newGem(s).Compare(newGem(equal[0])), ShouldEqual, 0)
+				}
+			})
+		}
+	})
+
+	Convey("Test RubyGems bump and release", t, func() {
+		So(newGem("5.3.1").Bump().String(), ShouldEqual, "5.4")
+		So(newGem("5.3.1.b.2").Bump().String(), ShouldEqual, "5.4")
+		So(newGem("5").Bump().String(), ShouldEqual, "6")
+		So(newGem("1.2.3.pre.4").Release().String(), ShouldEqual, "1.2.3")
+	})
+}
+
+func TestGemRequirement(t *testing.T) {
+	type test struct {
+		v        string
+		expected bool
+	}
+	tests := []struct {
+		requirement string
+		data        []test
+	}{
+		{"~> 2.2", []test{
+			{"2.1", false},
+			{"2.2", true},
+			{"2.9.9", true},
+			{"3.0", false},
+			{"3.0.a", false},
+		}},
+		{"~> 2.2.0", []test{
+			{"2.2.0", true},
+			{"2.2.99", true},
+			{"2.3", false},
+		}},
+		{"~> 5", []test{
+			{"4.9", false},
+			{"5.9", true},
+			{"6", false},
+		}},
+		{"~> 1.0.a", []test{
+			{"1.0.a", true},
+			{"1.0.b", true},
+			{"1.9", true},
+			{"2.0", false},
+		}},
+		{"~> 2.2, >= 2.2.1", []test{
+			{"2.2.0", false},
+			{"2.2.1", true},
+			{"2.8", true},
+			{"3.0", false},
+		}},
+		{"> 1.0, < 2, != 1.5", []test{
+			{"1.0", false},
+			{"1.0.1", true},
+			{"1.5", false},
+			{"1.5.0.1", true},
+			{"2.0", false},
+		}},
+		{"1.2", []test{
+			{"1.2.0", true},
+			{"1.2.1", false},
+		}},
+		{"<= 1.9.a", []test{
+			{"1.9.a", true},
+			{"1.9", false},
+		}},
+		{"", []test{
+			{"0", true},
+			{"1.0.a", true},
+			{"99.1", true},
+		}},
+		{"~>", nil},
+		{"~> junk", nil},
+		{">= 1,", nil},
+		{"=~ 1", nil},
+	}
+
+	Convey("Test RubyGems requirements", t, func() {
+		for _, tc := range tests {
+			Convey(tc.requirement, func() {
+				req, err := semver.ParseGemRequirement(tc.requirement)
+				if tc.data == nil {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					for _, td := range tc.data {
+						Convey(td.v, func() {
+							So(req.SatisfiedBy(newGem(td.v)), ShouldEqual, td.expected)
+						})
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestGemFromVersion(t *testing.T) {
+	tests := []struct {
+		version, gem string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"1.2.3+build.5", "1.2.3"},
+		{"1.0.0-x-y.2", "1.0.0.pre.x.y.2"},
+		{"1.0.0-rc1", "1.0.0.pre.rc.1"},
+		{"1.0.0--", "1.0.0.pre"},
+		{"1.0.0-a--b", "1.0.0.pre.a.b"},
+		{"1.0.0-rc-", "1.0.0.pre.rc"},
+		{"1.0.0-0a.-1", "1.0.0.pre.0.a.1"},
+	}
+
+	Convey("Test converting versions to RubyGems versions", t, func() {
+		for _, tc := range tests {
+			Convey(tc.version, func() {
+				g := semver.GemFromVersion(semver.New(tc.version))
+				So(g.String(), ShouldEqual, tc.gem)
+				So(g.Compare(semver.MustGem(semver.NewGemVersion(tc.gem))), ShouldEqual, 0)
+				So(g.IsPreRelease(), ShouldEqual, semver.New(tc.version).IsPreRelease())
+			})
+		}
+	})
+
+	Convey("Test ranges with hyphenated pre-releases", t, func() {
+		r, err := semver.ParseGemRange(">= 1.0")
+		So(err, ShouldBeNil)
+		So(r(semver.New("1.0.0-rc-")), ShouldBeFalse)
+		So(r(semver.New("1.0.1--")), ShouldBeTrue)
+	})
+}
+
+func TestParseGemRange(t *testing.T) {
+	Convey("Test RubyGems Range", t, func() {
+		r, err := semver.ParseGemRange("~> 1.4, != 1.5.2")
+		So(err, ShouldBeNil)
+		So(r(semver.New("1.3.9")), ShouldBeFalse)
+		So(r(semver.New("1.4.0")), ShouldBeTrue)
+		So(r(semver.New("1.5.2")), ShouldBeFalse)
+		So(r(semver.New("1.5.2+build.1")), ShouldBeFalse)
+		So(r(semver.New("1.9.0-rc.1")), ShouldBeTrue)
+		So(r(semver.New("2.0.0-rc.1")), ShouldBeFalse)
+		So(r(semver.New("2.0.0")), ShouldBeFalse)
+
+		So(semver.GemFromVersion(semver.New("1.0.0-x-y.2")).String(), ShouldEqual, "1.0.0.pre.x.y.2")
+
+		_, err = semver.ParseGemRange("~> ")
+		So(err, ShouldNotBeNil)
+	})
+}
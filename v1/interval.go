@@ -0,0 +1,259 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"math"
+	"sort"
+)
+
+// bound is an end of an interval of versions.
+type bound struct {
+	v         *Version
+	inclusive bool
+}
+
+// interval is a contiguous set of versions.  The lower bound is always set,
+// as 0.0.0-0 is the lowest version, while a nil upper bound is unbounded.
+type interval struct {
+	lower, upper bound
+}
+
+func (i interval) String() string {
+	s := "("
+	if i.lower.inclusive {
+		s = "["
+	}
+	s += i.lower.v.String() + ","
+	if i.upper.v == nil {
+		return s + ")"
+	}
+	if i.upper.inclusive {
+		return s + i.upper.v.String() + "]"
+	}
+	return s + i.upper.v.String() + ")"
+}
+
+// unbounded returns the interval of every version.
+func unbounded() interval {
+	return interval{lower: bound{v: lowestVersion(), inclusive: true}}
+}
+
+// empty returns true if no version is in i.
+func (i interval) empty() bool {
+	if i.upper.v == nil {
+		return false
+	}
+	c := i.lower.v.Compare(i.upper.v)
+	return c > 0 || c == 0 && !(i.lower.inclusive && i.upper.inclusive)
+}
+
+// contains returns true if v is in i.
+func (i interval) contains(v *Version) bool {
+	return !i.below(v) && !i.above(v)
+}
+
+// below returns true if v is lower than every version of i.
+func (i interval) below(v *Version) bool {
+	c := v.Compare(i.lower.v)
+	return c < 0 || c == 0 && !i.lower.inclusive
+}
+
+// above returns true if v is higher than every version of i.
+func (i interval) above(v *Version) bool {
+	if i.upper.v == nil {
+		return false
+	}
+	c := v.Compare(i.upper.v)
+	return c > 0 || c == 0 && !i.upper.inclusive
+}
+
+// raise returns i with its lower bound raised to b if b is higher.
+func (i interval) raise(b bound) interval {
+	c := b.v.Compare(i.lower.v)
+	if c > 0 || c == 0 && !b.inclusive {
+		i.lower = b
+	}
+	return i
+}
+
+// cut returns i with its upper bound lowered to b if b is lower.
+func (i interval) cut(b bound) interval {
+	if i.upper.v == nil {
+		i.upper = b
+		return i
+	}
+	c := b.v.Compare(i.upper.v)
+	if c < 0 || c == 0 && !b.inclusive {
+		i.upper = b
+	}
+	return i
+}
+
+// intersect returns the interval of the versions in both i and o.
+func (i interval) intersect(o interval) interval {
+	i = i.raise(o.lower)
+	if o.upper.v != nil {
+		i = i.cut(o.upper)
+	}
+	return i
+}
+
+// first returns the lowest version in i, or nil if i has no version.
+func (i interval) first() *Version {
+	v := i.lower.v.Clone()
+	if !i.lower.inclusive {
+		v = nextVersion(i.lower.v)
+	}
+	if v != nil && i.contains(v) {
+		return v
+	}
+	return nil
+}
+
+// restrict returns the intervals of the versions of i that also satisfy vr.
+func (i interval) restrict(vr *versionRange) []interval {
+	switch vr.op {
+	case "=":
+		return []interval{i.raise(bound{v: vr.v, inclusive: true}).cut(bound{v: vr.v, inclusive: true})}
+	case "!=":
+		if vr.upper != nil {
+			return []interval{i.cut(bound{v: vr.v}), i.raise(bound{v: vr.upper, inclusive: true})}
+		}
+		return []interval{i.cut(bound{v: vr.v}), i.raise(bound{v: vr.v})}
+	case ">":
+		return []interval{i.raise(bound{v: vr.v})}
+	case ">=":
+		return []interval{i.raise(bound{v: vr.v, inclusive: true})}
+	case "<":
+		return []interval{i.cut(bound{v: vr.v})}
+	default: // "<="
+		return []interval{i.cut(bound{v: vr.v, inclusive: true})}
+	}
+}
+
+// intervals returns the versions that satisfy the OR'ed groups of AND'ed
+// versionRanges as sorted, disjoint and non-empty intervals.
+func intervals(groups [][]*versionRange) []interval {
+	var all []interval
+	for _, g := range groups {
+		branch := []interval{unbounded()}
+		for _, vr := range g {
+			var restricted []interval
+			for _, i := range branch {
+				for _, r := range i.restrict(vr) {
+					if !r.empty() {
+						restricted = append(restricted, r)
+					}
+				}
+			}
+			branch = restricted
+		}
+		all = append(all, branch...)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		c := all[i].lower.v.Compare(all[j].lower.v)
+		return c < 0 || c == 0 && all[i].lower.inclusive && !all[j].lower.inclusive
+	})
+	var merged []interval
+	for _, i := range all {
+		if n := len(merged); n > 0 && !merged[n-1].disjoint(i) {
+			merged[n-1] = merged[n-1].union(i)
+			continue
+		}
+		merged = append(merged, i)
+	}
+	return merged
+}
+
+// searchIntervals returns the index of the first of the sorted intervals
+// that v is not above, which is the only one that may contain v.
+func searchIntervals(table []interval, v *Version) int {
+	lo, hi := 0, len(table)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if table[m].above(v) {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	return lo
+}
+
+// disjoint returns true if i ends before o starts, so that their union is
+// not an interval.  o must not start before i.
+func (i interval) disjoint(o interval) bool {
+	if i.upper.v == nil {
+		return false
+	}
+	c := i.upper.v.Compare(o.lower.v)
+	return c < 0 || c == 0 && !i.upper.inclusive && !o.lower.inclusive
+}
+
+// union returns the interval of the versions in either i or o, which must
+// overlap or touch, and o must not start before i.
+func (i interval) union(o interval) interval {
+	if i.upper.v == nil {
+		return i
+	}
+	if o.upper.v == nil {
+		i.upper = o.upper
+		return i
+	}
+	c := o.upper.v.Compare(i.upper.v)
+	if c > 0 || c == 0 && o.upper.inclusive {
+		i.upper = o.upper
+	}
+	return i
+}
+
+// nextVersion returns the lowest version above v: a pre-release of the next
+// release for a release, and v with an additional numeric identifier 0 for a
+// pre-release.  It returns nil if there is no next release.
+func nextVersion(v *Version) *Version {
+	if v.IsPreRelease() {
+		next := &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Metadata: Identifiers{}}
+		next.PreRelease = append(append(Identifiers{}, v.PreRelease...), Identifier{IsNum: true})
+		return next
+	}
+	next := nextRelease(v)
+	if next != nil {
+		next.PreRelease = Identifiers{{IsNum: true}}
+	}
+	return next
+}
+
+// nextRelease returns the release that follows the release of v, which is
+// the next patch version unless the patch version would overflow and carry
+// into the minor or major version.  It returns nil if the major version
+// would overflow as well.
+func nextRelease(v *Version) *Version {
+	next := &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, PreRelease: Identifiers{}, Metadata: Identifiers{}}
+	switch {
+	case next.Patch < math.MaxUint64:
+		next.Patch++
+	case next.Minor < math.MaxUint64:
+		next.Minor, next.Patch = next.Minor+1, 0
+	case next.Major < math.MaxUint64:
+		next.Major, next.Minor, next.Patch = next.Major+1, 0, 0
+	default:
+		return nil
+	}
+	return next
+}
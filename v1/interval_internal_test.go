@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIntervals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"*", []string{"[0.0.0-0,)"}},
+		{">=1.0.0 <2.0.0", []string{"[1.0.0,2.0.0)"}},
+		{"1.2.3", []string{"[1.2.3,1.2.3]"}},
+		{"!=1.2.3", []string{"[0.0.0-0,1.2.3)", "(1.2.3,)"}},
+		{"1.x || 1.5.x || 2.x", []string{"[1.0.0,2.0.0-0)", "[2.0.0,3.0.0-0)"}},
+		{"1.x || >=2.0.0-0 <3.0.0", []string{"[1.0.0,3.0.0)"}},
+		{"<=1.0.0 || >1.0.0 <2.0.0", []string{"[0.0.0-0,2.0.0)"}},
+		{"<1.0.0 || >1.0.0 <2.0.0", []string{"[0.0.0-0,1.0.0)", "(1.0.0,2.0.0)"}},
+		{"3.x || <1.0.0 || 1.0.0", []string{"[0.0.0-0,1.0.0]", "[3.0.0,4.0.0-0)"}},
+		{">=2.0.0 <1.0.0 || >=1.0.0 <1.0.0", nil},
+	}
+
+	Convey("Test intervals", t, func() {
+		for _, tc := range tests {
+			Convey(tc.input, func() {
+				var is []string
+				for _, i := range MustParseConstraint(tc.input).intervals() {
+					is = append(is, i.String())
+				}
+				So(is, ShouldResemble, tc.expected)
+			})
+		}
+	})
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.2.3", "1.2.4-0"},
+		{"1.2.3+build", "1.2.4-0"},
+		{"1.2.3-beta", "1.2.3-beta.0"},
+		{"0.0.0-0", "0.0.0-0.0"},
+		{"1.0.18446744073709551615", "1.1.0-0"},
+		{"1.18446744073709551615.18446744073709551615", "2.0.0-0"},
+		{"1.0.18446744073709551615-rc", "1.0.18446744073709551615-rc.0"},
+	}
+
+	Convey("Test next version", t, func() {
+		for _, tc := range tests {
+			Convey(tc.input, func() {
+				v := nextVersion(New(tc.input))
+				So(v, ShouldResemble, New(tc.expected))
+				So(v.GT(New(tc.input)), ShouldBeTrue)
+			})
+		}
+		So(nextVersion(New("18446744073709551615.18446744073709551615.18446744073709551615")), ShouldBeNil)
+		So(successor(New("18446744073709551615.18446744073709551615.18446744073709551615")), ShouldBeNil)
+		So(successor(New("1.0.18446744073709551615")), ShouldResemble, New("1.1.0"))
+	})
+}
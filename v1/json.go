@@ -35,3 +35,24 @@ func (v *Version) UnmarshalJSON(data []byte) (err error) {
 
 	return v.Set(versionString)
 }
+
+// MarshalJSON implements the encoding/json.Marshaler interface.
+func (c Constraint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON implements the encoding/json.Unmarshaler interface.  As with
+// the other types of encoding/json, null leaves c unchanged.
+func (c *Constraint) UnmarshalJSON(data []byte) (err error) {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var constraintString string
+
+	if err = json.Unmarshal(data, &constraintString); err != nil {
+		return
+	}
+
+	return c.Set(constraintString)
+}
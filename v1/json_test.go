@@ -54,3 +54,43 @@ func TestJSON(t *testing.T) {
 		})
 	})
 }
+
+func TestConstraintJSON(t *testing.T) {
+	type dependency struct {
+		Name       string            `json:"name"`
+		Constraint semver.Constraint `json:"constraint"`
+	}
+
+	Convey("Test marshall constraint", t, func() {
+		d := dependency{Name: "core", Constraint: *semver.MustParseConstraint("1.2.x  || 2")}
+		j, err := json.Marshal(d)
+		So(err, ShouldBeNil)
+		So(string(j), ShouldEqual, `{"name":"core","constraint":"1.2.x  || 2"}`)
+	})
+	Convey("Test unmarshall constraint", t, func() {
+		Convey("valid constraint", func() {
+			var d dependency
+			err := json.Unmarshal([]byte(`{"name":"core","constraint":"1.x || >=3.0.0"}`), &d)
+			So(err, ShouldBeNil)
+			So(d.Constraint.String(), ShouldEqual, "1.x || >=3.0.0")
+			So(d.Constraint.Range()(semver.New("1.9.0")), ShouldBeTrue)
+			So(d.Constraint.Range()(semver.New("2.0.0")), ShouldBeFalse)
+		})
+		Convey("invalid constraint", func() {
+			var d dependency
+			err := json.Unmarshal([]byte(`{"name":"core","constraint":">=1.2.3 ||"}`), &d)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("unmarshal a number constraint", func() {
+			var c semver.Constraint
+			err := json.Unmarshal([]byte("1234"), &c)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("unmarshal null", func() {
+			c := semver.MustParseConstraint("1.x")
+			err := json.Unmarshal([]byte("null"), c)
+			So(err, ShouldBeNil)
+			So(c.String(), ShouldEqual, "1.x")
+		})
+	})
+}
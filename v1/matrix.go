@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Combination is a version of each of a set of components, such as a
+// client, a server and a plugin, keyed by the name of the component.  A
+// component with a nil version is missing.
+type Combination map[string]*Version
+
+// String returns the components of c and their versions, ordered by name,
+// such as "client 2.5.0, server 3.1.0".
+func (c Combination) String() string {
+	components := make([]string, 0, len(c))
+	for component, v := range c {
+		if v != nil {
+			components = append(components, component)
+		}
+	}
+	sort.Strings(components)
+	for i, component := range components {
+		components[i] = component + " " + c[component].String()
+	}
+	return strings.Join(components, ", ")
+}
+
+// matrixRule requires the versions of a component that satisfy a range to
+// be deployed with versions of another component that satisfy another range.
+type matrixRule struct {
+	component, requires string
+	str, requiresStr    string
+	r, requiresRange    Range
+}
+
+func (r *matrixRule) String() string {
+	return fmt.Sprintf("%s %s requires %s %s", r.component, r.str, r.requires, r.requiresStr)
+}
+
+// check returns the reason why c does not satisfy r, or an empty string if
+// it does.  The rule does not apply if c has no version of its component.
+func (r *matrixRule) check(c Combination) string {
+	v := c[r.component]
+	if v == nil || !r.r(v) {
+		return ""
+	}
+	required := c[r.requires]
+	if required == nil {
+		return fmt.Sprintf("%s is missing", r.requires)
+	}
+	if !r.requiresRange(required) {
+		return fmt.Sprintf("%s %s does not satisfy %s", r.requires, required, r.requiresStr)
+	}
+	return ""
+}
+
+// CompatibilityMatrix declares which versions of several components work
+// together, with rules such as "server 3.x requires client >=2.4.0 <4" and
+// "plugin ^1.2 requires server >=3.1":
+//
+//	m := semver.NewCompatibilityMatrix()
+//	err := m.Require("server", "3.x", "client", ">=2.4.0 <4")
+//
+// A combination of versions is compatible if it satisfies every rule.
+type CompatibilityMatrix struct {
+	// ParseRange parses the ranges of the rules when they are added.  It
+	// defaults to ParseRange and can be set to another dialect, such as
+	// ParseCargoRange, whose ranges do not match pre-releases.
+	ParseRange func(string) (Range, error)
+
+	rules []*matrixRule
+}
+
+// NewCompatibilityMatrix returns a CompatibilityMatrix without rules, which
+// parses ranges with ParseRange.
+func NewCompatibilityMatrix() *CompatibilityMatrix {
+	return &CompatibilityMatrix{ParseRange: ParseRange}
+}
+
+// Require adds a rule that the versions of component that satisfy r are
+// only compatible with the versions of requires that satisfy requiresRange.
+// If either range could not be parsed an error is returned.
+func (m *CompatibilityMatrix) Require(component, r, requires, requiresRange string) error {
+	rule := &matrixRule{component: component, requires: requires, str: r, requiresStr: requiresRange}
+	var err error
+	if rule.r, err = m.ParseRange(r); err != nil {
+		return fmt.Errorf("range %q of %s: %w", r, component, err)
+	}
+	if rule.requiresRange, err = m.ParseRange(requiresRange); err != nil {
+		return fmt.Errorf("range %q of %s: %w", requiresRange, requires, err)
+	}
+	m.rules = append(m.rules, rule)
+	return nil
+}
+
+// Check returns the reasons why the versions of c are not compatible, which
+// is empty if they are.  A rule does not apply to a combination without its
+// component, but fails if the combination lacks the component it requires.
+func (m *CompatibilityMatrix) Check(c Combination) []Incompatibility {
+	var incompatibilities []Incompatibility
+	for _, rule := range m.rules {
+		if reason := rule.check(c); len(reason) > 0 {
+			incompatibilities = append(incompatibilities, Incompatibility{Rule: rule.String(), Reason: reason})
+		}
+	}
+	return incompatibilities
+}
+
+// Compatible returns true if the versions of c are compatible and false otherwise.
+func (m *CompatibilityMatrix) Compatible(c Combination) bool {
+	return len(m.Check(c)) == 0
+}
+
+// Combinations returns every compatible combination of a version of each
+// component of catalogs.  The combinations are ordered by the versions of
+// the components, ordered by name, from lowest to highest.  Nil versions in
+// catalogs are ignored, and a component without other versions is left out
+// of the combinations, as if it had no catalog.
+func (m *CompatibilityMatrix) Combinations(catalogs map[string]Versions) []Combination {
+	available := make(map[string]Versions, len(catalogs))
+	for component, versions := range catalogs {
+		for _, v := range versions {
+			if v != nil {
+				available[component] = append(available[component], v)
+			}
+		}
+	}
+	components := make([]string, 0, len(available))
+	for component := range available {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	sorted := make([]Versions, len(components))
+	for i, component := range components {
+		sorted[i] = available[component]
+		sort.Sort(sorted[i])
+	}
+
+	var combinations []Combination
+	partial := Combination{}
+	var walk func(int)
+	walk = func(n int) {
+		if n == len(components) {
+			combination := make(Combination, len(partial))
+			for component, v := range partial {
+				combination[component] = v
+			}
+			combinations = append(combinations, combination)
+			return
+		}
+		for _, v := range sorted[n] {
+			partial[components[n]] = v
+			if m.satisfiable(partial, available) {
+				walk(n + 1)
+			}
+		}
+		delete(partial, components[n])
+	}
+	walk(0)
+	return combinations
+}
+
+// satisfiable returns true if the partial combination satisfies every rule
+// that applies to it, that is every rule whose components either are in the
+// partial combination or cannot be added to it.
+func (m *CompatibilityMatrix) satisfiable(partial Combination, catalogs map[string]Versions) bool {
+	for _, rule := range m.rules {
+		_, hasComponent := partial[rule.component]
+		_, hasRequired := partial[rule.requires]
+		_, canAdd := catalogs[rule.requires]
+		if hasComponent && (hasRequired || !canAdd) && len(rule.check(partial)) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"strconv"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func matrix() *semver.CompatibilityMatrix {
+	m := semver.NewCompatibilityMatrix()
+	for _, rule := range [][4]string{
+		{"server", "3.x", "client", ">=2.4 <4"},
+		{"plugin", "^1.2", "server", ">=3.1"},
+	} {
+		if err := m.Require(rule[0], rule[1], rule[2], rule[3]); err != nil {
+			panic(err)
+		}
+	}
+	return m
+}
+
+func combination(components ...string) semver.Combination {
+	c := semver.Combination{}
+	for i := 0; i < len(components); i += 2 {
+		c[components[i]] = semver.New(components[i+1])
+	}
+	return c
+}
+
+func TestCompatibilityMatrix(t *testing.T) {
+	m := matrix()
+
+	Convey("Test checking combinations", t, func() {
+		tests := []struct {
+			combination semver.Combination
+			reasons     []string
+		}{
+			{combination("client", "2.5.0", "server", "3.1.0", "plugin", "1.2.0"), nil},
+			{combination("client", "4.0.0", "server", "3.1.0"), []string{
+				"server 3.x requires client >=2.4 <4: client 4.0.0 does not satisfy >=2.4 <4",
+			}},
+			{combination("client", "2.3.0", "server", "3.0.0", "plugin", "1.4.0"), []string{
+				"server 3.x requires client >=2.4 <4: client 2.3.0 does not satisfy >=2.4 <4",
+				"plugin ^1.2 requires server >=3.1: server 3.0.0 does not satisfy >=3.1",
+			}},
+			{combination("client", "1.0.0", "server", "2.0.0", "plugin", "1.1.0"), nil},
+			{combination("plugin", "1.2.0"), []string{"plugin ^1.2 requires server >=3.1: server is missing"}},
+			{combination("server", "2.0.0"), nil},
+			{combination("plugin", "2.0.0-beta.1", "server", "2.0.0"), nil},
+			{semver.Combination{"plugin": semver.New("1.2.0"), "server": nil}, []string{"plugin ^1.2 requires server >=3.1: server is missing"}},
+			{semver.Combination{"client": nil, "server": semver.New("3.1.0")}, []string{"server 3.x requires client >=2.4 <4: client is missing"}},
+			{semver.Combination{"client": nil}, nil},
+		}
+		for i, tc := range tests {
+			Convey(strconv.Itoa(i)+": "+tc.combination.String(), func() {
+				var reasons []string
+				for _, inc := range m.Check(tc.combination) {
+					reasons = append(reasons, inc.String())
+				}
+				So(reasons, ShouldResemble, tc.reasons)
+				So(m.Compatible(tc.combination), ShouldEqual, tc.reasons == nil)
+			})
+		}
+		So(semver.Combination{"client": nil, "server": semver.New("3.1.0")}.String(), ShouldEqual, "server 3.1.0")
+	})
+
+	Convey("Test listing compatible combinations", t, func() {
+		combinations := m.Combinations(map[string]semver.Versions{
+			"client": newVersions("4.0.0", "2.4.0", "2.0.0"),
+			"server": newVersions("3.2.0", "2.9.0", "3.0.0"),
+			"plugin": newVersions("1.2.0", "1.1.0"),
+		})
+		var strs []string
+		for _, c := range combinations {
+			So(m.Compatible(c), ShouldBeTrue)
+			strs = append(strs, c.String())
+		}
+		So(strs, ShouldResemble, []string{
+			"client 2.0.0, plugin 1.1.0, server 2.9.0",
+			"client 2.4.0, plugin 1.1.0, server 2.9.0",
+			"client 2.4.0, plugin 1.1.0, server 3.0.0",
+			"client 2.4.0, plugin 1.1.0, server 3.2.0",
+			"client 2.4.0, plugin 1.2.0, server 3.2.0",
+			"client 4.0.0, plugin 1.1.0, server 2.9.0",
+		})
+	})
+
+	Convey("Test rules requiring a component without a catalog", t, func() {
+		combinations := m.Combinations(map[string]semver.Versions{
+			"plugin": append(newVersions("1.1.0", "1.2.0"), nil),
+		})
+		So(len(combinations), ShouldEqual, 1)
+		So(combinations[0].String(), ShouldEqual, "plugin 1.1.0")
+
+		combinations = m.Combinations(map[string]semver.Versions{
+			"plugin": newVersions("1.1.0", "1.2.0"),
+			"server": {nil},
+			"client": {},
+		})
+		So(len(combinations), ShouldEqual, 1)
+		So(combinations[0].String(), ShouldEqual, "plugin 1.1.0")
+	})
+
+	Convey("Test other range dialects", t, func() {
+		m := semver.NewCompatibilityMatrix()
+		m.ParseRange = semver.ParseCargoRange
+		So(m.Require("server", "3.*", "client", ">=2.4, <4"), ShouldBeNil)
+		So(m.Require("plugin", ">>1.2", "server", ">=3.1"), ShouldNotBeNil)
+		So(m.Require("plugin", "1.*", "server", ">>3"), ShouldNotBeNil)
+		So(m.Compatible(combination("server", "3.0.0", "client", "2.4.0")), ShouldBeTrue)
+		So(m.Compatible(combination("server", "3.0.0", "client", "4.0.0-rc.1")), ShouldBeFalse)
+	})
+}
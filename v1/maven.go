@@ -0,0 +1,534 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// mavenQualifiers are the well known qualifiers in ascending order.  The
+// empty qualifier is a release.  Unknown qualifiers sort after all of them.
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+// mavenItem is an element of a parsed Maven version.  A nil mavenItem
+// represents a missing element when comparing versions of different length.
+type mavenItem interface {
+	compare(o mavenItem) int
+	isNull() bool
+	String() string
+}
+
+// mavenInt is a numeric item, stored without leading zeroes so that
+// arbitrarily large numbers can be compared.
+type mavenInt string
+
+// mavenString is a qualifier item.
+type mavenString string
+
+// mavenList is a sub-list of items, started by a '-' or by a transition
+// between digits and letters.
+type mavenList []mavenItem
+
+// MavenVersion represents a version that is ordered with the rules of
+// Maven's ComparableVersion.
+type MavenVersion struct {
+	original string
+	items    mavenList
+}
+
+// NewMavenVersion parses s to create an instance of MavenVersion.
+// Maven accepts almost any string as a version, so an error is only returned
+// if s is empty.
+func NewMavenVersion(s string) (*MavenVersion, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, errors.New("version string empty")
+	}
+	return &MavenVersion{original: s, items: parseMavenItems(strings.ToLower(strings.TrimSpace(s)))}, nil
+}
+
+// mavenFromVersion converts a Version into a MavenVersion using its string form.
+func mavenFromVersion(v *Version) *MavenVersion {
+	s := v.String()
+	return &MavenVersion{original: s, items: parseMavenItems(strings.ToLower(s))}
+}
+
+// MustMaven is a helper for wrapping NewMavenVersion and will panic if err is not nil.
+func MustMaven(m *MavenVersion, err error) *MavenVersion {
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func parseMavenItems(s string) mavenList {
+	root := &mavenNode{}
+	list := root
+	stack := []*mavenNode{root}
+
+	push := func() {
+		sub := &mavenNode{}
+		list.items = append(list.items, sub)
+		list = sub
+		stack = append(stack, sub)
+	}
+	isDigit := false
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				list.items = append(list.items, mavenInt(""))
+			} else {
+				list.items = append(list.items, newMavenItem(isDigit, s[start:i], false))
+			}
+			start = i + 1
+			if c == '-' {
+				push()
+			}
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				list.items = append(list.items, newMavenItem(false, s[start:i], true))
+				start = i
+				push()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.items = append(list.items, newMavenItem(true, s[start:i], false))
+				start = i
+				push()
+			}
+			isDigit = false
+		}
+	}
+	if len(s) > start {
+		list.items = append(list.items, newMavenItem(isDigit, s[start:], false))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+
+	return root.resolve()
+}
+
+func newMavenItem(isDigit bool, s string, followedByDigit bool) mavenItem {
+	if isDigit {
+		return mavenInt(strings.TrimLeft(s, "0"))
+	}
+	if followedByDigit && len(s) == 1 {
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := mavenAliases[s]; ok {
+		s = alias
+	}
+	return mavenString(s)
+}
+
+func (i mavenInt) compare(o mavenItem) int {
+	switch o := o.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case mavenInt:
+		if c := compareInt(len(i), len(o)); c != 0 {
+			return c
+		}
+		return strings.Compare(string(i), string(o))
+	default:
+		return 1
+	}
+}
+
+func (i mavenInt) isNull() bool {
+	return len(i) == 0
+}
+
+func (i mavenInt) String() string {
+	if i.isNull() {
+		return "0"
+	}
+	return string(i)
+}
+
+// comparableQualifier returns a string that orders qualifiers as Maven does.
+func (q mavenString) comparableQualifier() string {
+	for i, known := range mavenQualifiers {
+		if string(q) == known {
+			return strconv.Itoa(i)
+		}
+	}
+	return strconv.Itoa(len(mavenQualifiers)) + "-" + string(q)
+}
+
+func (q mavenString) compare(o mavenItem) int {
+	switch o := o.(type) {
+	case nil:
+		return strings.Compare(q.comparableQualifier(), mavenString("").comparableQualifier())
+	case mavenString:
+		return strings.Compare(q.comparableQualifier(), o.comparableQualifier())
+	default:
+		return -1
+	}
+}
+
+func (q mavenString) isNull() bool {
+	return len(q) == 0
+}
+
+func (q mavenString) String() string {
+	return string(q)
+}
+
+// mavenNode is a sub-list under construction while parsing.  Its items
+// are either a mavenItem or another *mavenNode.
+type mavenNode struct {
+	items []interface{}
+}
+
+// normalize removes trailing null items, stopping at the last non-list item.
+func (n *mavenNode) normalize() {
+	for i := len(n.items) - 1; i >= 0; i-- {
+		switch item := n.items[i].(type) {
+		case *mavenNode:
+			if len(item.items) != 0 {
+				continue
+			}
+		case mavenItem:
+			if !item.isNull() {
+				return
+			}
+		}
+		n.items = append(n.items[:i], n.items[i+1:]...)
+	}
+}
+
+// resolve converts the node into a mavenList.
+func (n *mavenNode) resolve() mavenList {
+	resolved := make(mavenList, len(n.items))
+	for i, item := range n.items {
+		if sub, ok := item.(*mavenNode); ok {
+			resolved[i] = sub.resolve()
+		} else {
+			resolved[i] = item.(mavenItem)
+		}
+	}
+	return resolved
+}
+
+func (l mavenList) compare(o mavenItem) int {
+	switch o := o.(type) {
+	case nil:
+		if len(l) == 0 {
+			return 0
+		}
+		return l[0].compare(nil)
+	case mavenInt:
+		return -1
+	case mavenString:
+		return 1
+	case mavenList:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var left, right mavenItem
+			if i < len(l) {
+				left = l[i]
+			}
+			if i < len(o) {
+				right = o[i]
+			}
+			var c int
+			if left == nil {
+				if right != nil {
+					c = -right.compare(nil)
+				}
+			} else {
+				c = left.compare(right)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func (l mavenList) isNull() bool {
+	return len(l) == 0
+}
+
+func (l mavenList) String() string {
+	var b strings.Builder
+	for i, item := range l {
+		if i > 0 {
+			if _, ok := item.(mavenList); ok {
+				b.WriteByte('-')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteString(item.String())
+	}
+	return b.String()
+}
+
+// Compare tests if m is less than, equal to, or greater than o using Maven's
+// ordering, returning -1, 0, or +1 respectively.
+func (m *MavenVersion) Compare(o *MavenVersion) int {
+	return m.items.compare(o.items)
+}
+
+// String returns the version string m was parsed from.
+func (m *MavenVersion) String() string {
+	return m.original
+}
+
+// Canonical returns the canonical form of m; two versions are equal if
+// their canonical forms are equal.
+func (m *MavenVersion) Canonical() string {
+	return m.items.String()
+}
+
+// Version converts m into a Version.
+//
+// Up to three leading numeric components become the major, minor and patch
+// numbers.  The well known pre-release qualifiers (alpha, beta, milestone,
+// rc and snapshot) and anything following them become pre-release
+// identifiers, which Semantic Versioning orders the same way Maven does.
+// Release qualifiers are dropped.  Any other qualifier, including sp, sorts
+// after the release in Maven and so becomes build metadata.
+func (m *MavenVersion) Version() (*Version, error) {
+	v := &Version{PreRelease: Identifiers{}, Metadata: Identifiers{}}
+
+	var numbers []uint64
+	items := m.items
+	for len(items) > 0 {
+		i, ok := items[0].(mavenInt)
+		if !ok {
+			break
+		}
+		if len(numbers) == versionComponents {
+			return nil, fmt.Errorf("maven version %q has more than %d numeric components", m, versionComponents)
+		}
+		n, err := strconv.ParseUint(i.String(), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, n)
+		items = items[1:]
+	}
+	numbers = append(numbers, 0, 0, 0)
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+
+	var qualifiers []string
+	flattenMavenItems(items, &qualifiers)
+	if len(qualifiers) == 0 {
+		return v, nil
+	}
+
+	target := &v.Metadata
+	switch qualifiers[0] {
+	case "":
+		qualifiers = qualifiers[1:]
+	case "alpha", "beta", "milestone", "rc", "snapshot":
+		target = &v.PreRelease
+	}
+	for _, q := range qualifiers {
+		id, err := newIdentifier(q, target == &v.PreRelease)
+		if err != nil {
+			return nil, fmt.Errorf("maven version %q has no Semantic Versioning equivalent: %s", m, err)
+		}
+		*target = append(*target, id)
+	}
+
+	return v, nil
+}
+
+func flattenMavenItems(items mavenList, result *[]string) {
+	for _, item := range items {
+		if l, ok := item.(mavenList); ok {
+			flattenMavenItems(l, result)
+		} else {
+			*result = append(*result, item.String())
+		}
+	}
+}
+
+// MavenVersions is an array of MavenVersion pointers for sorting.
+type MavenVersions []*MavenVersion
+
+func (s MavenVersions) Len() int {
+	return len(s)
+}
+
+func (s MavenVersions) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s MavenVersions) Less(i, j int) bool {
+	return s[i].Compare(s[j]) < 0
+}
+
+// mavenBound is one side of a Maven version interval.  A nil version is unbounded.
+type mavenBound struct {
+	v   *MavenVersion
+	cmp comparison
+}
+
+func (b mavenBound) contains(m *MavenVersion) bool {
+	return b.v == nil || b.cmp(m.Compare(b.v))
+}
+
+// ParseMavenRange parses a Maven version range and returns a Range.
+// If the range could not be parsed an error is returned.
+//
+// Valid ranges are:
+//   - "[1.0]" matches exactly 1.0
+//   - "[1.0,2.0)" matches 1.0 <= x < 2.0
+//   - "(1.0,2.0]" matches 1.0 < x <= 2.0
+//   - "(,1.0]" matches x <= 1.0
+//   - "[1.2,)" matches x >= 1.2
+//   - "(,1.0],[1.2,)" matches x <= 1.0 or x >= 1.2
+//
+// As in Maven, a bare version such as "1.0" is only a recommendation and
+// matches every version.  Versions are compared using Maven's ordering of
+// their string form.
+func ParseMavenRange(s string) (Range, error) {
+	spec := strings.Replace(s, " ", "", -1)
+	if len(spec) == 0 {
+		return nil, errors.New("range string empty")
+	}
+	if spec[0] != '[' && spec[0] != '(' {
+		if _, err := NewMavenVersion(spec); err != nil {
+			return nil, err
+		}
+		return func(*Version) bool { return true }, nil
+	}
+
+	var orFn Range
+	var previous *MavenVersion
+	for len(spec) > 0 {
+		end := strings.IndexAny(spec, ")]")
+		if end == -1 {
+			return nil, fmt.Errorf("unbounded interval in Maven range %q", s)
+		}
+		lower, upper, err := parseMavenInterval(spec[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("could not parse Maven range %q: %s", s, err)
+		}
+		if previous != nil && (lower.v == nil || lower.v.Compare(previous) < 0) {
+			return nil, fmt.Errorf("intervals overlap in Maven range %q", s)
+		}
+		previous = upper.v
+
+		rf := Range(func(v *Version) bool {
+			m := mavenFromVersion(v)
+			return lower.contains(m) && upper.contains(m)
+		})
+		if orFn == nil {
+			orFn = rf
+		} else {
+			orFn = orFn.OR(rf)
+		}
+
+		spec = spec[end+1:]
+		if len(spec) > 0 {
+			if spec[0] != ',' || len(spec) == 1 {
+				return nil, fmt.Errorf("expected ',' between intervals in Maven range %q", s)
+			}
+			spec = spec[1:]
+			if previous == nil {
+				return nil, fmt.Errorf("intervals overlap in Maven range %q", s)
+			}
+		}
+	}
+
+	return orFn, nil
+}
+
+// parseMavenInterval parses a single interval such as "[1.0,2.0)".
+func parseMavenInterval(s string) (lower, upper mavenBound, err error) {
+	if s[0] != '[' && s[0] != '(' {
+		return lower, upper, fmt.Errorf("interval %q must start with '[' or '('", s)
+	}
+	inclusiveLower := s[0] == '['
+	inclusiveUpper := s[len(s)-1] == ']'
+	body := s[1 : len(s)-1]
+
+	parts := strings.Split(body, ",")
+	switch len(parts) {
+	case 1:
+		if !inclusiveLower || !inclusiveUpper || len(body) == 0 {
+			return lower, upper, fmt.Errorf("single version interval %q must be inclusive", s)
+		}
+		v, err := NewMavenVersion(body)
+		if err != nil {
+			return lower, upper, err
+		}
+		return mavenBound{v, cmpGE}, mavenBound{v, cmpLE}, nil
+	case 2:
+	default:
+		return lower, upper, fmt.Errorf("interval %q has more than two bounds", s)
+	}
+
+	lower.cmp, upper.cmp = cmpGT, cmpLT
+	if inclusiveLower {
+		lower.cmp = cmpGE
+	}
+	if inclusiveUpper {
+		upper.cmp = cmpLE
+	}
+	if len(parts[0]) > 0 {
+		if lower.v, err = NewMavenVersion(parts[0]); err != nil {
+			return lower, upper, err
+		}
+	} else if inclusiveLower {
+		return lower, upper, fmt.Errorf("unbounded lower end of interval %q must be exclusive", s)
+	}
+	if len(parts[1]) > 0 {
+		if upper.v, err = NewMavenVersion(parts[1]); err != nil {
+			return lower, upper, err
+		}
+	} else if inclusiveUpper {
+		return lower, upper, fmt.Errorf("unbounded upper end of interval %q must be exclusive", s)
+	}
+	if lower.v != nil && upper.v != nil && lower.v.Compare(upper.v) > 0 {
+		return lower, upper, fmt.Errorf("lower bound of interval %q is greater than its upper bound", s)
+	}
+
+	return lower, upper, nil
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func newMaven(s string) *semver.MavenVersion {
+	return semver.MustMaven(semver.NewMavenVersion(s))
+}
+
+func checkMavenOrder(expected []string) {
+	for i := 1; i < len(expected); i++ {
+		low, high := newMaven(expected[i-1]), newMaven(expected[i])
+		So(low.Compare(high), ShouldEqual, -1)
+		So(high.Compare(low), ShouldEqual, 1)
+	}
+}
+
+func TestMavenCompare(t *testing.T) {
+	Convey("Test Maven qualifier ordering", t, func() {
+		checkMavenOrder([]string{
+			"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+			"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+			"1-1", "1-2", "1-123",
+		})
+	})
+
+	Convey("Test Maven number ordering", t, func() {
+		checkMavenOrder([]string{
+			"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1",
+			"2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m",
+		})
+	})
+
+	Convey("Test Maven equality", t, func() {
+		tests := [][]string{
+			{"1", "1.0", "1.0.0", "1-ga", "1-final", "1-release", "1.0.0-GA"},
+			{"1a1", "1-a1", "1-alpha-1", "1alpha1"},
+			{"1cr", "1rc", "1-CR"},
+			{"1m3", "1milestone3", "1-milestone-3"},
+			{"2.0.0.0000000000000000000000000001", "2.0.0.1"},
+		}
+		for _, equal := range tests {
+			Convey(equal[0], func() {
+				for _, s := range equal[1:] {
+					So(newMaven(s).Compare(newMaven(equal[0])), ShouldEqual, 0)
+					So(newMaven(s).Canonical(), ShouldEqual, newMaven(equal[0]).Canonical())
+				}
+			})
+		}
+	})
+
+	Convey("Test Maven sort", t, func() {
+		data := semver.MavenVersions{newMaven("1.0"), newMaven("1.0-SNAPSHOT"), newMaven("1.0-sp1"), newMaven("0.9")}
+		sort.Sort(data)
+		So(data[0].String(), ShouldEqual, "0.9")
+		So(data[1].String(), ShouldEqual, "1.0-SNAPSHOT")
+		So(data[2].String(), ShouldEqual, "1.0")
+		So(data[3].String(), ShouldEqual, "1.0-sp1")
+	})
+
+	Convey("Test empty Maven version", t, func() {
+		_, err := semver.NewMavenVersion(" ")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestMavenToVersion(t *testing.T) {
+	tests := []struct {
+		maven    string
+		expected string
+	}{
+		{"1", "1.0.0"},
+		{"1.2.3", "1.2.3"},
+		{"1.2-SNAPSHOT", "1.2.0-snapshot"},
+		{"1.0-alpha-1", "1.0.0-alpha.1"},
+		{"2.0.0.RELEASE", "2.0.0"},
+		{"31.1-jre", "31.1.0+jre"},
+		{"1.0-sp2", "1.0.0+sp.2"},
+		{"1.2.3.4", ""},
+		{"1.0-foo_bar", ""},
+	}
+
+	Convey("Test Maven to Version", t, func() {
+		for _, tc := range tests {
+			Convey(tc.maven, func() {
+				v, err := newMaven(tc.maven).Version()
+				if tc.expected == "" {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(v.String(), ShouldEqual, tc.expected)
+				}
+			})
+		}
+	})
+}
+
+func TestParseMavenRange(t *testing.T) {
+	type test struct {
+		v        string
+		expected bool
+	}
+	tests := []struct {
+		spec string
+		data []test
+	}{
+		{"1.0", []test{
+			{"0.1.0", true},
+			{"9.0.0", true},
+		}},
+		{"[1.0]", []test{
+			{"0.9.0", false},
+			{"1.0.0", true},
+			{"1.0.1", false},
+		}},
+		{"[1.0,2.0)", []test{
+			{"0.9.0", false},
+			{"1.0.0", true},
+			{"1.9.9", true},
+			{"2.0.0-rc.1", true},
+			{"2.0.0", false},
+		}},
+		{"(1.0,2.0]", []test{
+			{"1.0.0", false},
+			{"1.0.1", true},
+			{"2.0.0", true},
+			{"2.0.1", false},
+		}},
+		{"(,1.0],[1.2,)", []test{
+			{"0.1.0", true},
+			{"1.0.0", true},
+			{"1.1.0", false},
+			{"1.2.0", true},
+			{"5.0.0", true},
+		}},
+		{"[1.0, 1.5], [1.5, 2.0)", []test{
+			{"1.5.0", true},
+			{"1.7.0", true},
+		}},
+		{"[1.0.0-SNAPSHOT,)", []test{
+			{"1.0.0-snapshot", true},
+			{"1.0.0-alpha.1", false},
+		}},
+		{"[1.0,)", []test{
+			{"0.9.0", false},
+			{"1.0.0", true},
+		}},
+		// Errors
+		{"", nil},
+		{"[1.0", nil},
+		{"(1.0)", nil},
+		{"[]", nil},
+		{"[,1.0]", nil},
+		{"[1.0,)]", nil},
+		{"[2.0,1.0]", nil},
+		{"[1.0,2.0,3.0]", nil},
+		{"[1.0,2.0),", nil},
+		{"[1.0,2.0)[3.0,4.0)", nil},
+		{"[1.5,2.0),[1.0,1.6]", nil},
+		{"[1.0,),[2.0,3.0]", nil},
+	}
+
+	Convey("Test Maven range parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.spec, func() {
+				r, err := semver.ParseMavenRange(tc.spec)
+				if tc.data == nil {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					for _, td := range tc.data {
+						Convey(td.v, func() {
+							So(r(semver.New(td.v)), ShouldEqual, td.expected)
+						})
+					}
+				}
+			})
+		}
+	})
+}
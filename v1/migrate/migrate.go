@@ -0,0 +1,278 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+/*
+Package migrate plans and runs schema or data migrations keyed by semantic
+versions, entirely in-process.
+
+Each migration upgrades to its version and may revert it:
+
+	p, err := migrate.NewPlanner(
+		&migrate.Migration{Version: semver.New("1.1.0"), Name: "add-users", Up: addUsers, Down: dropUsers},
+		&migrate.Migration{Version: semver.New("1.2.0"), Name: "index-email", Up: indexEmail},
+	)
+	plan, err := p.Plan(current, target)
+	steps, err := plan.Run(ctx, false)
+
+An upgrade applies the migrations above the current version up to the
+target version from lowest to highest, and a downgrade reverts the
+migrations above the target version up to the current version from highest
+to lowest, as ordered by semver.Version.Compare.  A dry run returns the
+steps of a plan without running them.
+
+Plans that apply or revert the migration of a pre-release version, such as
+1.3.0-beta.1, must be allowed with Planner.AllowPreReleases.  A pre-release
+migration is never assumed to be included in a higher release: an allowed
+plan from 1.2.0 to 1.3.0 runs both 1.3.0-beta.1 and 1.3.0, and a downgrade
+back to 1.2.0 reverts both.
+*/
+package migrate // import "l7e.io/semver/v1/migrate"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"l7e.io/semver/v1"
+)
+
+var (
+	// ErrDuplicate is returned by NewPlanner if two migrations have the same
+	// precedence.
+	ErrDuplicate = errors.New("duplicate migration")
+	// ErrGap is returned by Planner.Plan if the current or target version is
+	// not the version of a migration, so that the migrations that were or
+	// should be applied are not known.  This is the only gap that is
+	// detected: as the versions of migrations need not be consecutive, a
+	// plan from 1.0.0 to 3.0.0 without a 2.x migration has no gap.
+	ErrGap = errors.New("no migration for version")
+	// ErrPreRelease is returned by Planner.Plan if a plan would apply or
+	// revert the migration of a pre-release version.
+	ErrPreRelease = errors.New("pre-release migration")
+	// ErrIrreversible is returned by Planner.Plan if a downgrade would revert
+	// a migration without a Down function.
+	ErrIrreversible = errors.New("irreversible migration")
+)
+
+// Migration is a change of a schema or of data that upgrades it to Version.
+type Migration struct {
+	Version *semver.Version
+	Name    string
+	// Up applies the migration.
+	Up func(context.Context) error
+	// Down reverts the migration.  A migration without Down cannot be
+	// downgraded.
+	Down func(context.Context) error
+}
+
+func (m *Migration) String() string {
+	if m.Name == "" {
+		return m.Version.String()
+	}
+	return m.Version.String() + " " + m.Name
+}
+
+// Direction tells whether a plan applies or reverts migrations.
+type Direction int
+
+const (
+	// Upgrade applies migrations from the lowest version to the highest.
+	Upgrade Direction = iota
+	// Downgrade reverts migrations from the highest version to the lowest.
+	Downgrade
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Upgrade:
+		return "upgrade"
+	case Downgrade:
+		return "downgrade"
+	default:
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+}
+
+// Step applies or reverts a migration.
+type Step struct {
+	Direction Direction
+	Migration *Migration
+}
+
+func (s Step) String() string {
+	return s.Direction.String() + " " + s.Migration.String()
+}
+
+func (s Step) run(ctx context.Context) error {
+	if s.Direction == Downgrade {
+		return s.Migration.Down(ctx)
+	}
+	return s.Migration.Up(ctx)
+}
+
+// StepError is returned by Plan.Run if a step fails.
+type StepError struct {
+	Step Step
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return e.Step.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error of the step.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Plan is an ordered list of the steps that migrate from a version to another.
+type Plan struct {
+	// From is the current version, nil if no migration was applied.
+	From *semver.Version
+	// To is the target version.
+	To        *semver.Version
+	Direction Direction
+	Steps     []Step
+}
+
+// String returns the steps of p, one per line.
+func (p *Plan) String() string {
+	steps := make([]string, len(p.Steps))
+	for i, s := range p.Steps {
+		steps[i] = s.String()
+	}
+	return strings.Join(steps, "\n")
+}
+
+// Run runs the steps of p in order and returns the steps that completed.  It
+// stops at the first step that fails, returning a *StepError, or when ctx is
+// done.  If dryRun is true no step is run and every step is returned.
+func (p *Plan) Run(ctx context.Context, dryRun bool) ([]Step, error) {
+	if dryRun {
+		return append([]Step(nil), p.Steps...), nil
+	}
+	var completed []Step
+	for _, s := range p.Steps {
+		if err := ctx.Err(); err != nil {
+			return completed, err
+		}
+		if err := s.run(ctx); err != nil {
+			return completed, &StepError{Step: s, Err: err}
+		}
+		completed = append(completed, s)
+	}
+	return completed, nil
+}
+
+// Planner plans migrations between versions.
+type Planner struct {
+	// AllowGaps allows the current and target versions of a plan to not be
+	// the version of a migration.
+	AllowGaps bool
+	// AllowPreReleases allows plans to run the migrations of pre-release
+	// versions.
+	AllowPreReleases bool
+
+	migrations []*Migration
+}
+
+// NewPlanner returns a Planner of the given migrations.  It returns an error
+// if a migration has no version or no Up function, or if two migrations have
+// the same precedence.
+func NewPlanner(migrations ...*Migration) (*Planner, error) {
+	sorted := make([]*Migration, len(migrations))
+	for i, m := range migrations {
+		if m.Version == nil {
+			return nil, fmt.Errorf("migration %d %q has no version", i, m.Name)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %s has no Up function", m)
+		}
+		sorted[i] = m
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version.LT(sorted[j].Version)
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i-1].Version.EQ(sorted[i].Version) {
+			return nil, fmt.Errorf("%w: %s and %s", ErrDuplicate, sorted[i-1], sorted[i])
+		}
+	}
+	return &Planner{migrations: sorted}, nil
+}
+
+// Migrations returns the migrations of p, ordered by version.
+func (p *Planner) Migrations() []*Migration {
+	return append([]*Migration(nil), p.migrations...)
+}
+
+// Plan returns the plan that migrates from current, nil if no migration was
+// applied, to target.  Unless allowed, it returns an error wrapping ErrGap
+// if current or target is not the version of a migration, and ErrPreRelease
+// if the plan would apply or revert a pre-release migration.  A downgrade
+// returns an error wrapping ErrIrreversible if a migration to revert has no
+// Down function.
+func (p *Planner) Plan(current, target *semver.Version) (*Plan, error) {
+	if target == nil {
+		return nil, errors.New("no target version")
+	}
+	if !p.AllowGaps {
+		for _, v := range []*semver.Version{current, target} {
+			if v != nil && !p.known(v) {
+				return nil, fmt.Errorf("%w %s", ErrGap, v)
+			}
+		}
+	}
+
+	plan := &Plan{From: current, To: target}
+	if current != nil && current.GT(target) {
+		plan.Direction = Downgrade
+		for i := len(p.migrations) - 1; i >= 0; i-- {
+			m := p.migrations[i]
+			if m.Version.LTE(current) && m.Version.GT(target) {
+				if m.Down == nil {
+					return nil, fmt.Errorf("%w: %s has no Down function", ErrIrreversible, m)
+				}
+				plan.Steps = append(plan.Steps, Step{Direction: Downgrade, Migration: m})
+			}
+		}
+	} else {
+		for _, m := range p.migrations {
+			if (current == nil || m.Version.GT(current)) && m.Version.LTE(target) {
+				plan.Steps = append(plan.Steps, Step{Direction: Upgrade, Migration: m})
+			}
+		}
+	}
+
+	if !p.AllowPreReleases {
+		for _, s := range plan.Steps {
+			if s.Migration.Version.IsPreRelease() {
+				return nil, fmt.Errorf("%w: %s", ErrPreRelease, s)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// known returns true if v is the version of a migration.
+func (p *Planner) known(v *semver.Version) bool {
+	i := sort.Search(len(p.migrations), func(i int) bool {
+		return p.migrations[i].Version.GE(v)
+	})
+	return i < len(p.migrations) && p.migrations[i].Version.EQ(v)
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+	"l7e.io/semver/v1/migrate"
+)
+
+// schema records the migrations that ran in place of a database.
+type schema struct {
+	log []string
+}
+
+func (s *schema) migration(version, name string, reversible bool) *migrate.Migration {
+	m := &migrate.Migration{
+		Version: semver.New(version),
+		Name:    name,
+		Up: func(context.Context) error {
+			s.log = append(s.log, "up "+version)
+			return nil
+		},
+	}
+	if reversible {
+		m.Down = func(context.Context) error {
+			s.log = append(s.log, "down "+version)
+			return nil
+		}
+	}
+	return m
+}
+
+func versionOrNil(s string) *semver.Version {
+	if s == "" {
+		return nil
+	}
+	return semver.New(s)
+}
+
+func TestPlan(t *testing.T) {
+	s := &schema{}
+	planner, err := migrate.NewPlanner(
+		s.migration("1.2.0", "index-email", true),
+		s.migration("1.0.0", "create-users", true),
+		s.migration("1.10.0", "add-audit", true),
+		s.migration("1.1.0", "add-roles", true),
+		s.migration("1.3.0-beta.1", "try-sharding", true),
+		s.migration("2.0.0", "split-accounts", false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Test planning migrations", t, func() {
+		tests := []struct {
+			current, target string
+			direction       migrate.Direction
+			steps           []string
+		}{
+			{"", "1.1.0", migrate.Upgrade, []string{"upgrade 1.0.0 create-users", "upgrade 1.1.0 add-roles"}},
+			{"1.1.0", "1.2.0", migrate.Upgrade, []string{"upgrade 1.2.0 index-email"}},
+			{"1.2.0", "1.2.0", migrate.Upgrade, nil},
+			{"1.10.0", "2.0.0", migrate.Upgrade, []string{"upgrade 2.0.0 split-accounts"}},
+			{"", "1.2.0", migrate.Upgrade, []string{
+				"upgrade 1.0.0 create-users",
+				"upgrade 1.1.0 add-roles",
+				"upgrade 1.2.0 index-email",
+			}},
+			{"1.3.0-beta.1", "2.0.0", migrate.Upgrade, []string{"upgrade 1.10.0 add-audit", "upgrade 2.0.0 split-accounts"}},
+			{"1.2.0", "1.0.0", migrate.Downgrade, []string{"downgrade 1.2.0 index-email", "downgrade 1.1.0 add-roles"}},
+		}
+		for _, tc := range tests {
+			Convey(tc.current+" to "+tc.target, func() {
+				plan, err := planner.Plan(versionOrNil(tc.current), semver.New(tc.target))
+				So(err, ShouldBeNil)
+				So(plan.Direction, ShouldEqual, tc.direction)
+				var steps []string
+				for _, s := range plan.Steps {
+					steps = append(steps, s.String())
+				}
+				So(steps, ShouldResemble, tc.steps)
+			})
+		}
+	})
+
+	Convey("Test rejected plans", t, func() {
+		tests := []struct {
+			name            string
+			current, target string
+			err             error
+			message         string
+		}{
+			{"unknown current version", "1.0.5", "1.2.0", migrate.ErrGap, "no migration for version 1.0.5"},
+			{"unknown target version", "1.0.0", "1.5.0", migrate.ErrGap, "no migration for version 1.5.0"},
+			{"pre-release target", "1.2.0", "1.3.0-beta.1", migrate.ErrPreRelease, "pre-release migration: upgrade 1.3.0-beta.1 try-sharding"},
+			{"pre-release current version", "1.3.0-beta.1", "1.2.0", migrate.ErrPreRelease, "pre-release migration: downgrade 1.3.0-beta.1 try-sharding"},
+			{"upgrade across a pre-release", "1.2.0", "2.0.0", migrate.ErrPreRelease, "pre-release migration: upgrade 1.3.0-beta.1 try-sharding"},
+			{"downgrade across a pre-release", "1.10.0", "1.1.0", migrate.ErrPreRelease, "pre-release migration: downgrade 1.3.0-beta.1 try-sharding"},
+			{"irreversible migration", "2.0.0", "1.10.0", migrate.ErrIrreversible, "irreversible migration: 2.0.0 split-accounts has no Down function"},
+		}
+		for _, tc := range tests {
+			Convey(tc.name, func() {
+				plan, err := planner.Plan(versionOrNil(tc.current), semver.New(tc.target))
+				So(plan, ShouldBeNil)
+				So(errors.Is(err, tc.err), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, tc.message)
+			})
+		}
+		_, err := planner.Plan(semver.New("1.0.0"), nil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test allowing gaps and pre-releases", t, func() {
+		planner.AllowGaps = true
+		defer func() { planner.AllowGaps, planner.AllowPreReleases = false, false }()
+
+		_, err := planner.Plan(semver.New("1.0.5"), semver.New("1.5.0"))
+		So(errors.Is(err, migrate.ErrPreRelease), ShouldBeTrue)
+
+		planner.AllowPreReleases = true
+		plan, err := planner.Plan(semver.New("1.0.5"), semver.New("1.5.0"))
+		So(err, ShouldBeNil)
+		So(plan.String(), ShouldEqual, "upgrade 1.1.0 add-roles\nupgrade 1.2.0 index-email\nupgrade 1.3.0-beta.1 try-sharding")
+
+		plan, err = planner.Plan(semver.New("1.2.0"), semver.New("1.3.0-beta.1"))
+		So(err, ShouldBeNil)
+		So(plan.String(), ShouldEqual, "upgrade 1.3.0-beta.1 try-sharding")
+
+		plan, err = planner.Plan(semver.New("1.3.0-beta.1"), semver.New("1.1.0"))
+		So(err, ShouldBeNil)
+		So(plan.String(), ShouldEqual, "downgrade 1.3.0-beta.1 try-sharding\ndowngrade 1.2.0 index-email")
+
+		plan, err = planner.Plan(semver.New("1.2.0"), semver.New("1.10.0"))
+		So(err, ShouldBeNil)
+		So(plan.String(), ShouldEqual, "upgrade 1.3.0-beta.1 try-sharding\nupgrade 1.10.0 add-audit")
+
+		plan, err = planner.Plan(semver.New("1.10.0"), semver.New("1.1.0"))
+		So(err, ShouldBeNil)
+		So(plan.String(), ShouldEqual, "downgrade 1.10.0 add-audit\ndowngrade 1.3.0-beta.1 try-sharding\ndowngrade 1.2.0 index-email")
+	})
+}
+
+func TestRun(t *testing.T) {
+	Convey("Test running plans", t, func() {
+		s := &schema{}
+		planner, err := migrate.NewPlanner(
+			s.migration("1.0.0", "create-users", true),
+			s.migration("1.1.0", "add-roles", true),
+			s.migration("1.2.0", "index-email", true),
+		)
+		So(err, ShouldBeNil)
+
+		plan, err := planner.Plan(nil, semver.New("1.2.0"))
+		So(err, ShouldBeNil)
+
+		steps, err := plan.Run(context.Background(), true)
+		So(err, ShouldBeNil)
+		So(len(steps), ShouldEqual, 3)
+		So(s.log, ShouldBeEmpty)
+
+		steps, err = plan.Run(context.Background(), false)
+		So(err, ShouldBeNil)
+		So(len(steps), ShouldEqual, 3)
+		So(s.log, ShouldResemble, []string{"up 1.0.0", "up 1.1.0", "up 1.2.0"})
+
+		plan, err = planner.Plan(semver.New("1.2.0"), semver.New("1.0.0"))
+		So(err, ShouldBeNil)
+		_, err = plan.Run(context.Background(), false)
+		So(err, ShouldBeNil)
+		So(s.log[3:], ShouldResemble, []string{"down 1.2.0", "down 1.1.0"})
+	})
+
+	Convey("Test running pre-release migrations", t, func() {
+		s := &schema{}
+		planner, err := migrate.NewPlanner(
+			s.migration("1.0.0", "create-users", true),
+			s.migration("1.1.0-beta.1", "try-roles", true),
+			s.migration("2.0.0", "split-accounts", true),
+		)
+		So(err, ShouldBeNil)
+
+		_, err = planner.Plan(semver.New("1.0.0"), semver.New("2.0.0"))
+		So(errors.Is(err, migrate.ErrPreRelease), ShouldBeTrue)
+		_, err = planner.Plan(semver.New("2.0.0"), semver.New("1.0.0"))
+		So(errors.Is(err, migrate.ErrPreRelease), ShouldBeTrue)
+
+		planner.AllowPreReleases = true
+		plan, err := planner.Plan(semver.New("1.0.0"), semver.New("2.0.0"))
+		So(err, ShouldBeNil)
+		_, err = plan.Run(context.Background(), false)
+		So(err, ShouldBeNil)
+		So(s.log, ShouldResemble, []string{"up 1.1.0-beta.1", "up 2.0.0"})
+
+		plan, err = planner.Plan(semver.New("2.0.0"), semver.New("1.0.0"))
+		So(err, ShouldBeNil)
+		_, err = plan.Run(context.Background(), false)
+		So(err, ShouldBeNil)
+		So(s.log[2:], ShouldResemble, []string{"down 2.0.0", "down 1.1.0-beta.1"})
+	})
+
+	Convey("Test failing steps", t, func() {
+		failure := errors.New("disk full")
+		s := &schema{}
+		broken := s.migration("1.1.0", "add-roles", true)
+		broken.Up = func(context.Context) error { return failure }
+		planner, err := migrate.NewPlanner(s.migration("1.0.0", "create-users", true), broken, s.migration("1.2.0", "index-email", true))
+		So(err, ShouldBeNil)
+		plan, err := planner.Plan(nil, semver.New("1.2.0"))
+		So(err, ShouldBeNil)
+
+		steps, err := plan.Run(context.Background(), false)
+		So(len(steps), ShouldEqual, 1)
+		So(errors.Is(err, failure), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "upgrade 1.1.0 add-roles: disk full")
+		var stepErr *migrate.StepError
+		So(errors.As(err, &stepErr), ShouldBeTrue)
+		So(stepErr.Step.Migration, ShouldEqual, broken)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		steps, err = plan.Run(ctx, false)
+		So(steps, ShouldBeEmpty)
+		So(err, ShouldEqual, context.Canceled)
+	})
+}
+
+func TestNewPlanner(t *testing.T) {
+	Convey("Test invalid migrations", t, func() {
+		s := &schema{}
+		_, err := migrate.NewPlanner(s.migration("1.0.0", "a", true), s.migration("1.0.0+build.5", "b", true))
+		So(errors.Is(err, migrate.ErrDuplicate), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "duplicate migration: 1.0.0 a and 1.0.0+build.5 b")
+
+		_, err = migrate.NewPlanner(&migrate.Migration{Name: "a"})
+		So(err, ShouldNotBeNil)
+		_, err = migrate.NewPlanner(&migrate.Migration{Version: semver.New("1.0.0")})
+		So(err, ShouldNotBeNil)
+
+		planner, err := migrate.NewPlanner(s.migration("1.1.0", "b", true), s.migration("1.0.0", "a", true))
+		So(err, ShouldBeNil)
+		So(len(planner.Migrations()), ShouldEqual, 2)
+		So(planner.Migrations()[0].Name, ShouldEqual, "a")
+	})
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+/*
+Package negotiate provides net/http middleware that negotiates the API
+version of a request.
+
+A client requests a range of versions with a header, "Accept-Version: >=2.1.0"
+by default, or with a parameter of a media type in the Accept header, such as
+"Accept: application/json; version=2.x".  The middleware picks the highest
+supported release that satisfies the range, or the highest pre-release if no
+release does, and stores it in the context of the request, where handlers
+retrieve it with FromContext:
+
+	n := negotiate.New(semver.New("1.4.2"), semver.New("2.0.0"), semver.New("2.1.0"))
+	http.Handle("/", n.Middleware(handler))
+
+If no supported version satisfies the range, the middleware responds with
+406 Not Acceptable and the list of supported versions.
+*/
+package negotiate // import "l7e.io/semver/v1/negotiate"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"l7e.io/semver/v1"
+)
+
+// DefaultHeader is the request header that holds the requested range.
+const DefaultHeader = "Accept-Version"
+
+// DefaultParameter is the media type parameter that holds the requested range.
+const DefaultParameter = "version"
+
+// Negotiator picks the API version of requests from a list of supported versions.
+type Negotiator struct {
+	// Header is the request header that holds the requested range.
+	Header string
+	// Parameter is the parameter of the media types in the Accept header
+	// that holds the requested range.  It is used if Header is not set.
+	Parameter string
+	// ParseRange parses requested ranges.  It defaults to semver.ParseRange,
+	// which accepts ranges such as ">=2.1.0 <3", "2.x" and "^2.1", and can be
+	// set to another dialect, such as semver.ParseComposerRange.
+	ParseRange func(string) (semver.Range, error)
+
+	supported semver.Versions
+}
+
+// New returns a Negotiator for the given supported versions, using the
+// default header and parameter names.
+func New(supported ...*semver.Version) *Negotiator {
+	versions := make(semver.Versions, len(supported))
+	copy(versions, supported)
+	sort.Sort(sort.Reverse(versions))
+
+	return &Negotiator{
+		Header:     DefaultHeader,
+		Parameter:  DefaultParameter,
+		ParseRange: semver.ParseRange,
+		supported:  versions,
+	}
+}
+
+// Supported returns the supported versions, highest first.
+func (n *Negotiator) Supported() []*semver.Version {
+	versions := make([]*semver.Version, len(n.supported))
+	copy(versions, n.supported)
+	return versions
+}
+
+// Negotiate returns the highest supported version that satisfies the range
+// requested by r, preferring releases to pre-releases: a pre-release is only
+// returned if no supported release satisfies the range, so that "2.x" picks
+// 2.1.3 rather than 2.2.0-beta.1.  If r does not request a range, every
+// supported version satisfies it.  The version is nil if no supported
+// version satisfies the range, and an error is returned if the range could
+// not be parsed or a media type with the parameter is malformed.
+func (n *Negotiator) Negotiate(r *http.Request) (*semver.Version, error) {
+	requested, err := n.requested(r)
+	if err != nil {
+		return nil, err
+	}
+	rf := func(*semver.Version) bool { return true }
+	if len(requested) > 0 {
+		parse := n.ParseRange
+		if parse == nil {
+			parse = semver.ParseRange
+		}
+		if rf, err = parse(requested); err != nil {
+			return nil, err
+		}
+	}
+
+	var preRelease *semver.Version
+	for _, v := range n.supported {
+		if !rf(v) {
+			continue
+		}
+		if !v.IsPreRelease() {
+			return v, nil
+		}
+		if preRelease == nil {
+			preRelease = v
+		}
+	}
+	return preRelease, nil
+}
+
+// requested returns the range requested by r, taken from the header or
+// else from the first media type in the Accept header with the parameter.
+// It returns an error if a media type that seems to have the parameter, such
+// as one with an unquoted range, could not be parsed.
+func (n *Negotiator) requested(r *http.Request) (string, error) {
+	if len(n.Header) > 0 {
+		if s := strings.TrimSpace(r.Header.Get(n.Header)); len(s) > 0 {
+			return s, nil
+		}
+	}
+	if len(n.Parameter) == 0 {
+		return "", nil
+	}
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range splitMediaRanges(accept) {
+			_, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				if hasParameter(mediaRange, n.Parameter) {
+					return "", fmt.Errorf("malformed media type %q: %s", strings.TrimSpace(mediaRange), err)
+				}
+				continue
+			}
+			if s := strings.TrimSpace(params[n.Parameter]); len(s) > 0 {
+				return s, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// hasParameter returns true if a parameter of mediaRange, which may be
+// malformed, is named name.
+func hasParameter(mediaRange, name string) bool {
+	parts := strings.Split(mediaRange, ";")
+	for _, p := range parts[1:] {
+		if i := strings.IndexByte(p, '='); i != -1 && strings.EqualFold(strings.TrimSpace(p[:i]), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitMediaRanges splits an Accept header on the commas that are not
+// inside a quoted parameter value, as a range such as ">=1.0.0, <2.0.0"
+// must be quoted.
+func splitMediaRanges(s string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			i++
+		case ',':
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// notAcceptable is the body of a 406 Not Acceptable response.
+type notAcceptable struct {
+	Error     string            `json:"error"`
+	Supported []*semver.Version `json:"supported"`
+}
+
+// Middleware returns a handler that negotiates the version of each request
+// before passing it to next with the version in its context.  It responds
+// with 400 Bad Request if the requested range could not be parsed, and 406
+// Not Acceptable if no supported version satisfies it.
+func (n *Negotiator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(n.Header) > 0 {
+			w.Header().Add("Vary", n.Header)
+		}
+		if len(n.Parameter) > 0 {
+			w.Header().Add("Vary", "Accept")
+		}
+
+		v, err := n.Negotiate(r)
+		if err != nil {
+			n.reject(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if v == nil {
+			requested, _ := n.requested(r)
+			n.reject(w, http.StatusNotAcceptable, "no supported version satisfies "+strconv.Quote(requested))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), v)))
+	})
+}
+
+func (n *Negotiator) reject(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(notAcceptable{Error: msg, Supported: n.Supported()})
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the negotiated version v.
+func NewContext(ctx context.Context, v *semver.Version) context.Context {
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FromContext returns the negotiated version stored in ctx, if any.
+func FromContext(ctx context.Context) (*semver.Version, bool) {
+	v, ok := ctx.Value(contextKey{}).(*semver.Version)
+	return v, ok
+}
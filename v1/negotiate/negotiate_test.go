@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package negotiate_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+	"l7e.io/semver/v1/negotiate"
+)
+
+func echo(w http.ResponseWriter, r *http.Request) {
+	v, ok := negotiate.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "no version", http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write([]byte(v.String()))
+}
+
+func serve(n *negotiate.Negotiator, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	n.Middleware(http.HandlerFunc(echo)).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	supported := []*semver.Version{semver.New("2.0.0"), semver.New("1.4.2"), semver.New("2.1.3"), semver.New("2.2.0-beta.1"), semver.New("3.0.0")}
+
+	Convey("Test negotiating versions", t, func() {
+		tests := []struct {
+			header   map[string]string
+			expected string
+		}{
+			{nil, "3.0.0"},
+			{map[string]string{"Accept-Version": ">=2.1.0 <=2.1.9"}, "2.1.3"},
+			{map[string]string{"Accept-Version": "1.x"}, "1.4.2"},
+			{map[string]string{"Accept-Version": "^2.1"}, "2.1.3"},
+			{map[string]string{"Accept-Version": "~1.4"}, "1.4.2"},
+			{map[string]string{"Accept": "application/json; version=^2.0"}, "2.1.3"},
+			{map[string]string{"Accept-Version": "2.2.0-beta.1"}, "2.2.0-beta.1"},
+			{map[string]string{"Accept-Version": ">=2.2.0-0 <3.0.0"}, "2.2.0-beta.1"},
+			{map[string]string{"Accept-Version": "<2.0.0 || 2.2.0-beta.1"}, "1.4.2"},
+			{map[string]string{"Accept": "application/vnd.example+json; version=2.0.0"}, "2.0.0"},
+			{map[string]string{"Accept": `text/html, application/json; q=0.9; version=">=1.0.0 <2.0.0"`}, "1.4.2"},
+			{map[string]string{"Accept": `application/json; version=">=1.0.0 <2.0.0"`, "Accept-Version": "2.x"}, "2.1.3"},
+			{map[string]string{"Accept": "application/json"}, "3.0.0"},
+		}
+		for _, tc := range tests {
+			Convey(fmt.Sprint(tc.header), func() {
+				rec := serve(negotiate.New(supported...), tc.header)
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				So(rec.Body.String(), ShouldEqual, tc.expected)
+				So(rec.Header().Values("Vary"), ShouldResemble, []string{"Accept-Version", "Accept"})
+			})
+		}
+	})
+
+	Convey("Test preferring releases without a range", t, func() {
+		rec := serve(negotiate.New(semver.New("1.0.0"), semver.New("2.0.0-rc.1")), nil)
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(rec.Body.String(), ShouldEqual, "1.0.0")
+
+		rec = serve(negotiate.New(semver.New("2.0.0-rc.1")), nil)
+		So(rec.Body.String(), ShouldEqual, "2.0.0-rc.1")
+	})
+
+	Convey("Test other range dialects", t, func() {
+		n := negotiate.New(supported...)
+		n.ParseRange = semver.ParseCargoRange
+		rec := serve(n, map[string]string{"Accept-Version": "^2.1"})
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(rec.Body.String(), ShouldEqual, "2.1.3")
+	})
+
+	Convey("Test custom header names", t, func() {
+		n := negotiate.New(supported...)
+		n.Header = "X-API-Version"
+		n.Parameter = ""
+		rec := serve(n, map[string]string{"X-API-Version": "1.4.2", "Accept": "application/json; version=2.0.0"})
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(rec.Body.String(), ShouldEqual, "1.4.2")
+		So(rec.Header().Values("Vary"), ShouldResemble, []string{"X-API-Version"})
+
+		rec = serve(n, map[string]string{"Accept": "application/json; version=2.0.0"})
+		So(rec.Body.String(), ShouldEqual, "3.0.0")
+	})
+
+	Convey("Test unacceptable versions", t, func() {
+		rec := serve(negotiate.New(supported...), map[string]string{"Accept-Version": ">=4.0.0"})
+		So(rec.Code, ShouldEqual, http.StatusNotAcceptable)
+		So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+		var body struct {
+			Error     string   `json:"error"`
+			Supported []string `json:"supported"`
+		}
+		So(json.Unmarshal(rec.Body.Bytes(), &body), ShouldBeNil)
+		So(body.Error, ShouldContainSubstring, ">=4.0.0")
+		So(body.Supported, ShouldResemble, []string{"3.0.0", "2.2.0-beta.1", "2.1.3", "2.0.0", "1.4.2"})
+
+		rec = serve(negotiate.New(), nil)
+		So(rec.Code, ShouldEqual, http.StatusNotAcceptable)
+	})
+
+	Convey("Test invalid ranges", t, func() {
+		rec := serve(negotiate.New(supported...), map[string]string{"Accept-Version": ">=foo"})
+		So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+		for _, accept := range []string{
+			"application/json; version=>=1.0.0 <2.0.0",
+			"application/json; Version=>=1.0.0",
+			`application/json; version=">=1.0.0`,
+			"text/html, application/json; version=1.x; version=2.x",
+		} {
+			rec = serve(negotiate.New(supported...), map[string]string{"Accept": accept})
+			So(rec.Code, ShouldEqual, http.StatusBadRequest)
+		}
+
+		rec = serve(negotiate.New(supported...), map[string]string{"Accept": "application/json; charset=>utf-8, text/html"})
+		So(rec.Code, ShouldEqual, http.StatusOK)
+		So(rec.Body.String(), ShouldEqual, "3.0.0")
+	})
+}
+
+func TestContext(t *testing.T) {
+	Convey("Test versions in contexts", t, func() {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		_, ok := negotiate.FromContext(req.Context())
+		So(ok, ShouldBeFalse)
+
+		ctx := negotiate.NewContext(req.Context(), semver.New("1.2.3"))
+		v, ok := negotiate.FromContext(ctx)
+		So(ok, ShouldBeTrue)
+		So(v.String(), ShouldEqual, "1.2.3")
+	})
+}
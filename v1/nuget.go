@@ -0,0 +1,369 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const nugetComponents = 4
+
+// NuGetVersion represents a NuGet package version.  It is a Semantic
+// Versioning 2.0 version with an optional fourth revision number, and
+// pre-release identifiers are compared case-insensitively.
+type NuGetVersion struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Revision   uint64
+	PreRelease Identifiers
+	Metadata   Identifiers
+}
+
+// NewNuGetVersion parses s to create an instance of NuGetVersion.
+// Unlike Version, one to four numeric components with leading zeroes are
+// accepted, as NuGet does.
+func NewNuGetVersion(s string) (*NuGetVersion, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil, errors.New("version string empty")
+	}
+
+	n := &NuGetVersion{PreRelease: Identifiers{}, Metadata: Identifiers{}}
+	if i := strings.IndexRune(s, '+'); i != -1 {
+		for _, str := range strings.Split(s[i+1:], ".") {
+			id, err := newIdentifier(str, false)
+			if err != nil {
+				return nil, err
+			}
+			n.Metadata = append(n.Metadata, id)
+		}
+		s = s[:i]
+	}
+	if i := strings.IndexRune(s, '-'); i != -1 {
+		for _, str := range strings.Split(s[i+1:], ".") {
+			id, err := newIdentifier(str, false)
+			if err != nil {
+				return nil, err
+			}
+			n.PreRelease = append(n.PreRelease, id)
+		}
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > nugetComponents {
+		return nil, fmt.Errorf("more than %d version numbers in %q", nugetComponents, s)
+	}
+	components := []*uint64{&n.Major, &n.Minor, &n.Patch, &n.Revision}
+	for i, p := range parts {
+		if len(p) == 0 || !containsOnly(p, numbers) {
+			return nil, fmt.Errorf("invalid character(expected) found in version number %q", p)
+		}
+		num, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		*components[i] = num
+	}
+
+	return n, nil
+}
+
+// MustNuGet is a helper for wrapping NewNuGetVersion and will panic if err is not nil.
+func MustNuGet(n *NuGetVersion, err error) *NuGetVersion {
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// NuGetFromVersion converts a Version into a NuGetVersion with a zero revision.
+func NuGetFromVersion(v *Version) *NuGetVersion {
+	return &NuGetVersion{
+		Major:      v.Major,
+		Minor:      v.Minor,
+		Patch:      v.Patch,
+		PreRelease: v.PreRelease.Clone(),
+		Metadata:   v.Metadata.Clone(),
+	}
+}
+
+// Version normalizes n into a Version.  Numeric pre-release identifiers
+// with leading zeroes, which NuGet accepts, become alphanumeric identifiers.
+// A version with a non-zero revision has no Semantic Versioning equivalent
+// and an error is returned.
+func (n *NuGetVersion) Version() (*Version, error) {
+	if n.Revision != 0 {
+		return nil, fmt.Errorf("NuGet version %q has a revision", n)
+	}
+	return &Version{
+		Major:      n.Major,
+		Minor:      n.Minor,
+		Patch:      n.Patch,
+		PreRelease: n.PreRelease.Clone(),
+		Metadata:   n.Metadata.Clone(),
+	}, nil
+}
+
+// IsPreRelease returns true if n is a pre-release version, false otherwise.
+func (n *NuGetVersion) IsPreRelease() bool {
+	return len(n.PreRelease) != 0
+}
+
+// Compare tests if n is less than, equal to, or greater than o, returning
+// -1, 0, or +1 respectively.  Build metadata is ignored.
+func (n *NuGetVersion) Compare(o *NuGetVersion) int {
+	if c := compareUint(n.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(n.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(n.Patch, o.Patch); c != 0 {
+		return c
+	}
+	if c := compareUint(n.Revision, o.Revision); c != 0 {
+		return c
+	}
+	return foldIdentifiers(n.PreRelease).Compare(foldIdentifiers(o.PreRelease))
+}
+
+func foldIdentifiers(ids Identifiers) Identifiers {
+	folded := ids.Clone()
+	for i := range folded {
+		folded[i].Str = strings.ToLower(folded[i].Str)
+	}
+	return folded
+}
+
+// Normalized returns the normalized form of n, as used by NuGet to identify
+// packages: the revision is only included if it is not zero, and build
+// metadata is omitted.
+func (n *NuGetVersion) Normalized() string {
+	b := make([]byte, 0, 8)
+	b = strconv.AppendUint(b, n.Major, 10)
+	b = append(b, '.')
+	b = strconv.AppendUint(b, n.Minor, 10)
+	b = append(b, '.')
+	b = strconv.AppendUint(b, n.Patch, 10)
+	if n.Revision != 0 {
+		b = append(b, '.')
+		b = strconv.AppendUint(b, n.Revision, 10)
+	}
+	if len(n.PreRelease) > 0 {
+		b = append(b, '-')
+		b = append(b, n.PreRelease.String()...)
+	}
+	return string(b)
+}
+
+// String returns the normalized form of n including build metadata.
+func (n *NuGetVersion) String() string {
+	if len(n.Metadata) == 0 {
+		return n.Normalized()
+	}
+	return n.Normalized() + "+" + n.Metadata.String()
+}
+
+// NuGetVersions is an array of NuGetVersion pointers for sorting.
+type NuGetVersions []*NuGetVersion
+
+func (s NuGetVersions) Len() int {
+	return len(s)
+}
+
+func (s NuGetVersions) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s NuGetVersions) Less(i, j int) bool {
+	return s[i].Compare(s[j]) < 0
+}
+
+// nugetBound is one side of a NuGet version interval.  A nil version is unbounded.
+type nugetBound struct {
+	v   *NuGetVersion
+	cmp comparison
+}
+
+func (b nugetBound) contains(n *NuGetVersion) bool {
+	return b.v == nil || b.cmp(n.Compare(b.v))
+}
+
+// ParseNuGetRange parses a NuGet version range and returns a Range.
+// If the range could not be parsed an error is returned.
+//
+// Valid ranges are:
+//   - "1.0" matches x >= 1.0
+//   - "[1.0]" matches exactly 1.0
+//   - "[1.0,2.0)" matches 1.0 <= x < 2.0
+//   - "(,1.0]" and "(1.0,)" match x <= 1.0 and x > 1.0
+//
+// Floating versions match every version they could float to:
+//   - "*" and "1.*" and "1.2.*" match stable versions with the given prefix
+//   - "1.0.0-*" and "1.0.0-beta*" match 1.0.0 and its pre-releases whose
+//     pre-release starts with the given prefix
+//   - "1.*-*" and "*-*" also match pre-releases
+func ParseNuGetRange(s string) (Range, error) {
+	spec := strings.Replace(s, " ", "", -1)
+	if len(spec) == 0 {
+		return nil, errors.New("range string empty")
+	}
+	if strings.ContainsRune(spec, '*') {
+		return parseNuGetFloatRange(spec)
+	}
+
+	var lower, upper nugetBound
+	if spec[0] != '[' && spec[0] != '(' {
+		v, err := NewNuGetVersion(spec)
+		if err != nil {
+			return nil, err
+		}
+		lower = nugetBound{v, cmpGE}
+	} else {
+		if spec[len(spec)-1] != ']' && spec[len(spec)-1] != ')' {
+			return nil, fmt.Errorf("interval %q must end with ']' or ')'", s)
+		}
+		var err error
+		if lower, upper, err = parseNuGetInterval(spec); err != nil {
+			return nil, fmt.Errorf("could not parse NuGet range %q: %s", s, err)
+		}
+	}
+
+	return func(v *Version) bool {
+		n := NuGetFromVersion(v)
+		return lower.contains(n) && upper.contains(n)
+	}, nil
+}
+
+func parseNuGetInterval(s string) (lower, upper nugetBound, err error) {
+	inclusiveLower := s[0] == '['
+	inclusiveUpper := s[len(s)-1] == ']'
+	parts := strings.Split(s[1:len(s)-1], ",")
+
+	switch len(parts) {
+	case 1:
+		if !inclusiveLower || !inclusiveUpper || len(parts[0]) == 0 {
+			return lower, upper, fmt.Errorf("single version interval %q must be inclusive", s)
+		}
+		v, err := NewNuGetVersion(parts[0])
+		if err != nil {
+			return lower, upper, err
+		}
+		return nugetBound{v, cmpGE}, nugetBound{v, cmpLE}, nil
+	case 2:
+	default:
+		return lower, upper, fmt.Errorf("interval %q has more than two bounds", s)
+	}
+	if len(parts[0]) == 0 && len(parts[1]) == 0 {
+		return lower, upper, fmt.Errorf("interval %q has no bounds", s)
+	}
+
+	lower.cmp, upper.cmp = cmpGT, cmpLT
+	if inclusiveLower {
+		lower.cmp = cmpGE
+	}
+	if inclusiveUpper {
+		upper.cmp = cmpLE
+	}
+	if len(parts[0]) > 0 {
+		if lower.v, err = NewNuGetVersion(parts[0]); err != nil {
+			return lower, upper, err
+		}
+	}
+	if len(parts[1]) > 0 {
+		if upper.v, err = NewNuGetVersion(parts[1]); err != nil {
+			return lower, upper, err
+		}
+	}
+	if lower.v != nil && upper.v != nil {
+		c := lower.v.Compare(upper.v)
+		if c > 0 || c == 0 && !(inclusiveLower && inclusiveUpper) {
+			return lower, upper, fmt.Errorf("interval %q is empty", s)
+		}
+	}
+
+	return lower, upper, nil
+}
+
+// parseNuGetFloatRange parses a floating version such as "1.*" or "1.0.0-beta*".
+func parseNuGetFloatRange(s string) (Range, error) {
+	release, pre := s, ""
+	floatPre := false
+	if i := strings.IndexRune(s, '-'); i != -1 {
+		release, pre = s[:i], s[i+1:]
+		if !strings.HasSuffix(pre, "*") || strings.Count(pre, "*") != 1 {
+			return nil, fmt.Errorf("floating pre-release must end with '*' in %q", s)
+		}
+		pre = strings.ToLower(strings.TrimSuffix(pre, "*"))
+		if len(pre) > 0 && !containsOnly(pre, alphanum+".") {
+			return nil, fmt.Errorf("invalid character found in floating pre-release %q", s)
+		}
+		floatPre = true
+	}
+
+	var prefix []uint64
+	floatRelease := false
+	for i, p := range strings.Split(release, ".") {
+		if p == "*" {
+			if i == nugetComponents || floatRelease {
+				return nil, fmt.Errorf("invalid floating version %q", s)
+			}
+			floatRelease = true
+			continue
+		}
+		if floatRelease || len(p) == 0 || !containsOnly(p, numbers) || i == nugetComponents {
+			return nil, fmt.Errorf("invalid floating version %q", s)
+		}
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		prefix = append(prefix, n)
+	}
+	if floatRelease && floatPre && len(pre) > 0 {
+		return nil, fmt.Errorf("floating release cannot have a pre-release prefix in %q", s)
+	}
+	if !floatRelease && !floatPre {
+		return nil, fmt.Errorf("invalid floating version %q", s)
+	}
+
+	return func(v *Version) bool {
+		n := NuGetFromVersion(v)
+		components := []uint64{n.Major, n.Minor, n.Patch, n.Revision}
+		for i, p := range prefix {
+			if components[i] != p {
+				return false
+			}
+		}
+		if !floatRelease {
+			for _, num := range components[len(prefix):] {
+				if num != 0 {
+					return false
+				}
+			}
+		}
+		if !n.IsPreRelease() {
+			return true
+		}
+		return floatPre && strings.HasPrefix(strings.ToLower(n.PreRelease.String()), pre)
+	}, nil
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func newNuGet(s string) *semver.NuGetVersion {
+	return semver.MustNuGet(semver.NewNuGetVersion(s))
+}
+
+func TestNewNuGetVersion(t *testing.T) {
+	tests := []struct {
+		input      string
+		normalized string
+		full       string
+	}{
+		{"1", "1.0.0", "1.0.0"},
+		{"1.0", "1.0.0", "1.0.0"},
+		{"1.01.1", "1.1.1", "1.1.1"},
+		{"1.0.0.0", "1.0.0", "1.0.0"},
+		{"1.2.3.4", "1.2.3.4", "1.2.3.4"},
+		{"1.0.0-Beta.1+git.abc", "1.0.0-Beta.1", "1.0.0-Beta.1+git.abc"},
+		{"1.0.0-beta01", "1.0.0-beta01", "1.0.0-beta01"},
+		{"", "", ""},
+		{"1.2.3.4.5", "", ""},
+		{"1..2", "", ""},
+		{"a.b", "", ""},
+		{"1.0-", "", ""},
+		{"1.0-beta_1", "", ""},
+	}
+
+	Convey("Test NuGet version parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.input, func() {
+				n, err := semver.NewNuGetVersion(tc.input)
+				if tc.normalized == "" {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(n.Normalized(), ShouldEqual, tc.normalized)
+					So(n.String(), ShouldEqual, tc.full)
+				}
+			})
+		}
+	})
+}
+
+func TestNuGetCompare(t *testing.T) {
+	Convey("Test NuGet ordering", t, func() {
+		expected := []string{
+			"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta", "1.0.0-rc.1", "1.0.0", "1.0.0.1", "1.0.0.10", "1.0.1", "2.0.0",
+		}
+		data := make(semver.NuGetVersions, len(expected))
+		for i := range expected {
+			data[len(data)-1-i] = newNuGet(expected[i])
+		}
+
+		sort.Sort(data)
+
+		for i, n := range data {
+			So(n.String(), ShouldEqual, expected[i])
+		}
+	})
+
+	Convey("Test NuGet equality", t, func() {
+		So(newNuGet("1.0.0-BETA").Compare(newNuGet("1.0.0-beta")), ShouldEqual, 0)
+		So(newNuGet("1.0").Compare(newNuGet("1.0.0.0")), ShouldEqual, 0)
+		So(newNuGet("1.0.0+a").Compare(newNuGet("1.0.0+b")), ShouldEqual, 0)
+	})
+}
+
+func TestNuGetConversion(t *testing.T) {
+	Convey("Test NuGet to Version", t, func() {
+		v, err := newNuGet("1.02.3.0-RC.1+sha.5").Version()
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, semver.New("1.2.3-RC.1+sha.5"))
+
+		_, err = newNuGet("1.2.3.4").Version()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test Version to NuGet", t, func() {
+		n := semver.NuGetFromVersion(semver.New("1.2.3-beta.2+build"))
+		So(n.String(), ShouldEqual, "1.2.3-beta.2+build")
+		So(n.Revision, ShouldEqual, 0)
+	})
+}
+
+func TestParseNuGetRange(t *testing.T) {
+	type test struct {
+		v        string
+		expected bool
+	}
+	tests := []struct {
+		spec string
+		data []test
+	}{
+		{"1.0", []test{
+			{"0.9.0", false},
+			{"1.0.0", true},
+			{"5.0.0", true},
+		}},
+		{"[1.0]", []test{
+			{"1.0.0", true},
+			{"1.0.1", false},
+		}},
+		{"(1.0,)", []test{
+			{"1.0.0", false},
+			{"1.0.1-alpha", true},
+		}},
+		{"[1.0, 2.0)", []test{
+			{"1.0.0", true},
+			{"1.9.9", true},
+			{"2.0.0-beta", true},
+			{"2.0.0", false},
+		}},
+		{"(,1.0.0.1]", []test{
+			{"1.0.0", true},
+			{"1.0.1-alpha", false},
+		}},
+		{"[1.0.0-BETA,1.0.0]", []test{
+			{"1.0.0-alpha", false},
+			{"1.0.0-beta", true},
+			{"1.0.0-rc", true},
+		}},
+		{"*", []test{
+			{"0.0.1", true},
+			{"2.0.0", true},
+			{"2.0.0-beta", false},
+		}},
+		{"1.*", []test{
+			{"0.9.0", false},
+			{"1.0.0", true},
+			{"1.9.0", true},
+			{"1.9.0-beta", false},
+			{"2.0.0", false},
+		}},
+		{"1.2.*", []test{
+			{"1.2.0", true},
+			{"1.2.9", true},
+			{"1.3.0", false},
+		}},
+		{"1.0.0-*", []test{
+			{"1.0.0-alpha", true},
+			{"1.0.0", true},
+			{"1.0.1", false},
+			{"1.0.1-alpha", false},
+		}},
+		{"1.0.0-Beta*", []test{
+			{"1.0.0-alpha", false},
+			{"1.0.0-beta.2", true},
+			{"1.0.0-beta2", true},
+			{"1.0.0", true},
+		}},
+		{"1.*-*", []test{
+			{"1.5.0-alpha", true},
+			{"2.0.0-alpha", false},
+		}},
+		{"*-*", []test{
+			{"0.0.1-alpha", true},
+		}},
+		// Errors
+		{"", nil},
+		{"(1.0)", nil},
+		{"[]", nil},
+		{"(,)", nil},
+		{"[1.0", nil},
+		{"[2.0,1.0]", nil},
+		{"(1.0,1.0)", nil},
+		{"[1.0,2.0,3.0]", nil},
+		{"[1.*,2.0]", nil},
+		{"1.*.1", nil},
+		{"1.**", nil},
+		{"1.0.0-*beta", nil},
+		{"1.*-beta*", nil},
+		{"1.2.3.4.*", nil},
+	}
+
+	Convey("Test NuGet range parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.spec, func() {
+				r, err := semver.ParseNuGetRange(tc.spec)
+				if tc.data == nil {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					for _, td := range tc.data {
+						Convey(td.v, func() {
+							So(r(semver.New(td.v)), ShouldEqual, td.expected)
+						})
+					}
+				}
+			})
+		}
+	})
+}
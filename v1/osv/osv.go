@@ -0,0 +1,335 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+/*
+Package osv matches versions against the affected ranges of OSV
+vulnerability records, as described in https://ossf.github.io/osv-schema/.
+
+Only ranges of type SEMVER and ECOSYSTEM are evaluated, and the versions of
+ECOSYSTEM ranges must be semantic versions.  Other range types, such as GIT,
+are ignored.  Listed versions that are not semantic versions, such as the
+"1.0rc1" of a PyPI record, cannot match a semantic version and are skipped.
+*/
+package osv // import "l7e.io/semver/v1/osv"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"l7e.io/semver/v1"
+)
+
+// The range types that are evaluated.
+const (
+	TypeSemVer    = "SEMVER"
+	TypeEcosystem = "ECOSYSTEM"
+)
+
+// TypeGit is the range type of commit hashes, which cannot be compared as
+// versions.  GIT ranges are ignored.
+const TypeGit = "GIT"
+
+// Vulnerability is the part of an OSV record that describes what is affected.
+type Vulnerability struct {
+	ID       string     `json:"id"`
+	Summary  string     `json:"summary,omitempty"`
+	Affected []Affected `json:"affected"`
+}
+
+// Affected lists the affected versions of a single package.
+type Affected struct {
+	Package  Package  `json:"package"`
+	Ranges   []Range  `json:"ranges,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+}
+
+// Package identifies an affected package.
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl,omitempty"`
+}
+
+// Range is a sequence of events that introduce and fix a vulnerability.
+type Range struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo,omitempty"`
+	Events []Event `json:"events"`
+}
+
+// Event is a single event of a Range, of which exactly one field is set.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Parse decodes an OSV record from JSON.
+func Parse(data []byte) (*Vulnerability, error) {
+	vuln := &Vulnerability{}
+	if err := json.Unmarshal(data, vuln); err != nil {
+		return nil, err
+	}
+	return vuln, nil
+}
+
+// Check reports whether version v of the named package is affected by vuln,
+// and if so, the first version that is not.  The fixed version is nil if
+// v is not affected or if no fixed version is known.
+func (vuln *Vulnerability) Check(ecosystem, name string, v *semver.Version) (affected bool, fixed *semver.Version, err error) {
+	var ranges []semver.Range
+	var candidates []*semver.Version
+	for i := range vuln.Affected {
+		a := &vuln.Affected[i]
+		if a.Package.Ecosystem != ecosystem || a.Package.Name != name {
+			continue
+		}
+		r, err := a.Range()
+		if err != nil {
+			return false, nil, fmt.Errorf("%s: %s", vuln.ID, err)
+		}
+		ranges = append(ranges, r)
+		affected = affected || r(v)
+		for _, r := range a.Ranges {
+			if r.Type == TypeSemVer || r.Type == TypeEcosystem {
+				fixes, _ := r.fixes()
+				candidates = append(candidates, fixes...)
+			}
+		}
+	}
+	if !affected {
+		return false, nil, nil
+	}
+
+	sort.Sort(semver.Versions(candidates))
+	for _, c := range candidates {
+		if c.LE(v) {
+			continue
+		}
+		stillAffected := false
+		for _, r := range ranges {
+			stillAffected = stillAffected || r(c)
+		}
+		if !stillAffected {
+			return true, c, nil
+		}
+	}
+
+	return true, nil, nil
+}
+
+// IsAffected reports whether v is affected, either because it is listed in
+// the versions of a or because it is in one of the SEMVER or ECOSYSTEM ranges.
+func (a *Affected) IsAffected(v *semver.Version) (bool, error) {
+	r, err := a.Range()
+	if err != nil {
+		return false, err
+	}
+	return r(v), nil
+}
+
+// Range returns a semver.Range that matches the affected versions.  Listed
+// versions that are not semantic versions are skipped, as no semantic
+// version equals them.
+func (a *Affected) Range() (semver.Range, error) {
+	var listed semver.Versions
+	for _, s := range a.Versions {
+		if version, err := semver.NewVersion(s); err == nil {
+			listed = append(listed, version)
+		}
+	}
+	rf := semver.Range(func(v *semver.Version) bool {
+		for _, version := range listed {
+			if v.EQ(version) {
+				return true
+			}
+		}
+		return false
+	})
+	for _, r := range a.Ranges {
+		if r.Type != TypeSemVer && r.Type != TypeEcosystem {
+			continue
+		}
+		f, err := r.Range()
+		if err != nil {
+			return nil, err
+		}
+		rf = rf.OR(f)
+	}
+	return rf, nil
+}
+
+// fixes returns the versions of the fixed events of r.
+func (r *Range) fixes() ([]*semver.Version, error) {
+	var fixes []*semver.Version
+	for _, e := range r.Events {
+		if len(e.Fixed) == 0 {
+			continue
+		}
+		v, err := semver.NewVersion(e.Fixed)
+		if err != nil {
+			return nil, err
+		}
+		fixes = append(fixes, v)
+	}
+	return fixes, nil
+}
+
+// event is a parsed Event.  A nil version is "0" for an introduced event
+// and "*" for a limit.
+type event struct {
+	kind string
+	v    *semver.Version
+}
+
+const (
+	kindIntroduced   = "introduced"
+	kindFixed        = "fixed"
+	kindLastAffected = "last_affected"
+	kindLimit        = "limit"
+)
+
+func (e Event) parse() (event, error) {
+	var kinds, value []string
+	for _, field := range []struct{ kind, value string }{
+		{kindIntroduced, e.Introduced},
+		{kindFixed, e.Fixed},
+		{kindLastAffected, e.LastAffected},
+		{kindLimit, e.Limit},
+	} {
+		if len(field.value) > 0 {
+			kinds = append(kinds, field.kind)
+			value = append(value, field.value)
+		}
+	}
+	if len(kinds) != 1 {
+		return event{}, errors.New("event must have exactly one of introduced, fixed, last_affected or limit")
+	}
+
+	parsed := event{kind: kinds[0]}
+	if (parsed.kind == kindIntroduced && value[0] == "0") || (parsed.kind == kindLimit && value[0] == "*") {
+		return parsed, nil
+	}
+	v, err := semver.NewVersion(value[0])
+	if err != nil {
+		return event{}, fmt.Errorf("could not parse %s version: %s", parsed.kind, err)
+	}
+	parsed.v = v
+	return parsed, nil
+}
+
+// compare orders events by version, with "0" first and "*" last.
+func (e event) compare(o event) int {
+	switch {
+	case e.v == nil && o.v == nil:
+		return 0
+	case e.v == nil:
+		if e.kind == kindLimit {
+			return 1
+		}
+		return -1
+	case o.v == nil:
+		if o.kind == kindLimit {
+			return -1
+		}
+		return 1
+	}
+	return e.v.Compare(o.v)
+}
+
+// Range returns a semver.Range that matches the versions affected by r.
+// The events are sorted by version and each introduced event opens an
+// interval that the next fixed or last_affected event closes.  If r has
+// limit events, only versions below one of the limits are matched.
+//
+// An error is returned if r is not a SEMVER or ECOSYSTEM range or if one
+// of its versions could not be parsed.
+func (r *Range) Range() (semver.Range, error) {
+	if r.Type != TypeSemVer && r.Type != TypeEcosystem {
+		return nil, fmt.Errorf("unsupported range type %q", r.Type)
+	}
+
+	var events, limits []event
+	hasIntroduced := false
+	for _, e := range r.Events {
+		parsed, err := e.parse()
+		if err != nil {
+			return nil, err
+		}
+		if parsed.kind == kindLimit {
+			limits = append(limits, parsed)
+			continue
+		}
+		hasIntroduced = hasIntroduced || parsed.kind == kindIntroduced
+		events = append(events, parsed)
+	}
+	if !hasIntroduced {
+		return nil, errors.New("range has no introduced event")
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].compare(events[j]) < 0
+	})
+
+	rf := semver.Range(func(*semver.Version) bool { return false })
+	var open *event
+	for i := range events {
+		e := events[i]
+		switch {
+		case e.kind == kindIntroduced:
+			if open == nil {
+				open = &e
+			}
+		case open != nil:
+			rf = rf.OR(interval(open.v, e))
+			open = nil
+		}
+	}
+	if open != nil {
+		rf = rf.OR(interval(open.v, event{}))
+	}
+
+	if len(limits) == 0 {
+		return rf, nil
+	}
+	return rf.AND(func(v *semver.Version) bool {
+		for _, l := range limits {
+			if l.v == nil || v.LT(l.v) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// interval returns a Range from lower, or from any version if lower is nil,
+// up to the given fixed or last_affected event.
+func interval(lower *semver.Version, upper event) semver.Range {
+	return func(v *semver.Version) bool {
+		if lower != nil && v.LT(lower) {
+			return false
+		}
+		switch upper.kind {
+		case kindFixed:
+			return v.LT(upper.v)
+		case kindLastAffected:
+			return v.LE(upper.v)
+		}
+		return true
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package osv_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+	"l7e.io/semver/v1/osv"
+)
+
+func load(name string) *osv.Vulnerability {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	So(err, ShouldBeNil)
+	vuln, err := osv.Parse(data)
+	So(err, ShouldBeNil)
+	return vuln
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		fixture, ecosystem, name string
+		data                     []struct {
+			v        string
+			affected bool
+			fixed    string
+		}
+	}{
+		{"GHSA-semver.json", "Go", "example.com/parser", []struct {
+			v        string
+			affected bool
+			fixed    string
+		}{
+			{"0.0.1", true, "1.2.3"},
+			{"1.2.2", true, "1.2.3"},
+			{"1.2.3", false, ""},
+			{"1.2.9", false, ""},
+			{"1.3.0", true, "1.3.5"},
+			{"1.3.5", false, ""},
+			{"2.0.0", false, ""},
+		}},
+		{"GHSA-semver.json", "npm", "parser", []struct {
+			v        string
+			affected bool
+			fixed    string
+		}{
+			{"2.0.0-alpha", false, ""},
+			{"2.0.0-beta.1", true, "2.0.1"},
+			{"2.0.0", true, "2.0.1"},
+			{"2.0.1", false, ""},
+		}},
+		{"OSV-last-affected.json", "Go", "example.com/archive", []struct {
+			v        string
+			affected bool
+			fixed    string
+		}{
+			{"1.9.8", false, ""},
+			{"1.9.9", true, ""},
+			{"2.0.0", true, ""},
+			{"2.4.1", true, ""},
+			{"2.4.2", false, ""},
+		}},
+		{"OSV-limit.json", "Go", "example.com/server", []struct {
+			v        string
+			affected bool
+			fixed    string
+		}{
+			{"0.1.0", true, ""},
+			{"2.9.9", true, ""},
+			{"3.0.0", false, ""},
+		}},
+		{"OSV-overlapping.json", "Go", "example.com/codec", []struct {
+			v        string
+			affected bool
+			fixed    string
+		}{
+			{"1.0.0", true, "1.2.0"},
+			{"1.0.6", true, "1.2.0"},
+			{"1.1.0", true, "1.2.0"},
+			{"1.2.0", false, ""},
+		}},
+		{"OSV-ecosystem-versions.json", "PyPI", "example-lib", []struct {
+			v        string
+			affected bool
+			fixed    string
+		}{
+			{"0.9.0", false, ""},
+			{"1.0.0", true, ""},
+			{"1.0.1", true, ""},
+			{"1.0.2", false, ""},
+		}},
+		{"GHSA-semver.json", "Go", "example.com/other", []struct {
+			v        string
+			affected bool
+			fixed    string
+		}{
+			{"1.0.0", false, ""},
+		}},
+	}
+
+	Convey("Test checking versions against OSV records", t, func() {
+		for _, tc := range tests {
+			Convey(tc.fixture+" "+tc.ecosystem+"/"+tc.name, func() {
+				vuln := load(tc.fixture)
+				for _, td := range tc.data {
+					Convey(td.v, func() {
+						affected, fixed, err := vuln.Check(tc.ecosystem, tc.name, semver.New(td.v))
+						So(err, ShouldBeNil)
+						So(affected, ShouldEqual, td.affected)
+						if td.fixed == "" {
+							So(fixed, ShouldBeNil)
+						} else {
+							So(fixed, ShouldNotBeNil)
+							So(fixed.String(), ShouldEqual, td.fixed)
+						}
+					})
+				}
+			})
+		}
+	})
+
+	Convey("Test invalid OSV records", t, func() {
+		vuln := load("OSV-invalid-event.json")
+		_, _, err := vuln.Check("Go", "example.com/broken", semver.New("1.0.0"))
+		So(err, ShouldNotBeNil)
+
+		_, err = osv.Parse([]byte(`{"id": 1}`))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestRange(t *testing.T) {
+	Convey("Test converting ranges", t, func() {
+		vuln := load("GHSA-semver.json")
+		r, err := vuln.Affected[0].Ranges[0].Range()
+		So(err, ShouldBeNil)
+		So(r(semver.New("1.2.2")), ShouldBeTrue)
+		So(r(semver.New("1.2.3")), ShouldBeFalse)
+
+		_, err = vuln.Affected[0].Ranges[1].Range()
+		So(err, ShouldNotBeNil)
+
+		affected, err := vuln.Affected[0].IsAffected(semver.New("1.3.4"))
+		So(err, ShouldBeNil)
+		So(affected, ShouldBeTrue)
+	})
+
+	Convey("Test invalid ranges", t, func() {
+		tests := []osv.Range{
+			{Type: osv.TypeSemVer},
+			{Type: osv.TypeSemVer, Events: []osv.Event{{Fixed: "1.0.0"}}},
+			{Type: osv.TypeSemVer, Events: []osv.Event{{Introduced: "1.0"}}},
+			{Type: osv.TypeEcosystem, Events: []osv.Event{{Introduced: "0"}, {}}},
+			{Type: osv.TypeEcosystem, Events: []osv.Event{{Introduced: "0"}, {Limit: "x"}}},
+			{Type: osv.TypeGit, Events: []osv.Event{{Introduced: "0"}}},
+		}
+		for _, r := range tests {
+			_, err := r.Range()
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
@@ -0,0 +1,631 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440Pattern is the version scheme regular expression from PEP 440, Appendix B.
+var pep440Pattern = regexp.MustCompile(`^(?i)\s*v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?P<pre>[-_.]?(?P<pre_l>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?P<post>(?:-(?P<post_n1>[0-9]+))|(?:[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?))?` +
+	`(?P<dev>[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?` +
+	`\s*$`)
+
+// PEP440Version represents a Python package version as defined by PEP 440.
+type PEP440Version struct {
+	Epoch   uint64
+	Release []uint64
+	// Pre is one of "a", "b" or "rc", or empty if this is not a pre-release.
+	Pre     string
+	PreNum  uint64
+	HasPost bool
+	Post    uint64
+	HasDev  bool
+	Dev     uint64
+	Local   Identifiers
+	// original is the text the version was parsed from, which arbitrary
+	// equality (===) compares against.
+	original string
+}
+
+// NewPEP440Version parses s to create an instance of PEP440Version.
+// It will return an error if s does not adhere to PEP 440.
+func NewPEP440Version(s string) (*PEP440Version, error) {
+	m := pep440Pattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid PEP 440 version %q", s)
+	}
+	group := func(name string) string {
+		for i, n := range pep440Pattern.SubexpNames() {
+			if n == name {
+				return m[i]
+			}
+		}
+		return ""
+	}
+
+	p := &PEP440Version{Local: Identifiers{}, original: strings.TrimSpace(s)}
+	var err error
+
+	if e := group("epoch"); e != "" {
+		if p.Epoch, err = strconv.ParseUint(e, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range strings.Split(group("release"), ".") {
+		n, err := strconv.ParseUint(r, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		p.Release = append(p.Release, n)
+	}
+
+	if l := group("pre_l"); l != "" {
+		p.Pre = normalizePEP440PreLabel(l)
+		if p.PreNum, err = parseOptionalUint(group("pre_n")); err != nil {
+			return nil, err
+		}
+	}
+
+	if group("post") != "" {
+		p.HasPost = true
+		n := group("post_n1")
+		if n == "" {
+			n = group("post_n2")
+		}
+		if p.Post, err = parseOptionalUint(n); err != nil {
+			return nil, err
+		}
+	}
+
+	if group("dev") != "" {
+		p.HasDev = true
+		if p.Dev, err = parseOptionalUint(group("dev_n")); err != nil {
+			return nil, err
+		}
+	}
+
+	if l := group("local"); l != "" {
+		for _, str := range strings.FieldsFunc(strings.ToLower(l), isPEP440Separator) {
+			id, err := newIdentifier(str, false)
+			if err != nil {
+				return nil, err
+			}
+			p.Local = append(p.Local, id)
+		}
+	}
+
+	return p, nil
+}
+
+// MustPEP440 is a helper for wrapping NewPEP440Version and will panic if err is not nil.
+func MustPEP440(p *PEP440Version, err error) *PEP440Version {
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// PEP440FromVersion converts a Version into its PEP 440 equivalent.
+//
+// Pre-release identifiers of the form alpha.N, beta.N, rc.N (and their
+// short forms a, b and c) map onto PEP 440 pre-releases and dev.N maps onto
+// a development release.  Build metadata becomes the local version label.
+// An error is returned for any other pre-release identifiers.
+func PEP440FromVersion(v *Version) (*PEP440Version, error) {
+	p := &PEP440Version{
+		Release: []uint64{v.Major, v.Minor, v.Patch},
+		Local:   Identifiers{},
+	}
+
+	pre := v.PreRelease
+	if len(pre) > 0 && !pre[0].IsNum {
+		label := strings.ToLower(pre[0].Str)
+		switch label {
+		case "dev":
+			p.HasDev = true
+		case "alpha", "a", "beta", "b", "c", "rc", "pre", "preview":
+			p.Pre = normalizePEP440PreLabel(label)
+		default:
+			return nil, fmt.Errorf("pre-release %q has no PEP 440 equivalent", pre)
+		}
+		switch {
+		case len(pre) == 1:
+		case len(pre) == 2 && pre[1].IsNum:
+			p.PreNum = pre[1].Num
+			if p.HasDev {
+				p.Dev, p.PreNum = p.PreNum, 0
+			}
+		default:
+			return nil, fmt.Errorf("pre-release %q has no PEP 440 equivalent", pre)
+		}
+	} else if len(pre) > 0 {
+		return nil, fmt.Errorf("pre-release %q has no PEP 440 equivalent", pre)
+	}
+
+	for _, id := range v.Metadata {
+		for _, str := range strings.FieldsFunc(strings.ToLower(id.String()), isPEP440Separator) {
+			local, err := newIdentifier(str, false)
+			if err != nil {
+				return nil, err
+			}
+			p.Local = append(p.Local, local)
+		}
+	}
+
+	return p, nil
+}
+
+// Version converts p into an equivalent Version.
+//
+// The conversion is lossless for versions with a zero epoch, at most three
+// release segments and no post or development release combined with a
+// pre-release.  The local version label becomes build metadata and a
+// development release becomes a dev pre-release; note that Semantic Versioning
+// orders dev after alpha and beta pre-releases.  Any other version has no
+// Semantic Versioning equivalent and an error is returned.
+func (p *PEP440Version) Version() (*Version, error) {
+	if p.Epoch != 0 {
+		return nil, fmt.Errorf("PEP 440 version %q has an epoch", p)
+	}
+	if len(p.Release) > versionComponents {
+		return nil, fmt.Errorf("PEP 440 version %q has more than %d release segments", p, versionComponents)
+	}
+	if p.HasPost {
+		return nil, fmt.Errorf("PEP 440 version %q is a post-release", p)
+	}
+	if p.HasDev && p.Pre != "" {
+		return nil, fmt.Errorf("PEP 440 version %q is a development pre-release", p)
+	}
+
+	v := &Version{PreRelease: Identifiers{}, Metadata: p.Local.Clone()}
+	release := make([]uint64, versionComponents)
+	copy(release, p.Release)
+	v.Major, v.Minor, v.Patch = release[0], release[1], release[2]
+
+	switch {
+	case p.Pre == "a":
+		v.PreRelease = Identifiers{{Str: "alpha"}, {Num: p.PreNum, IsNum: true}}
+	case p.Pre == "b":
+		v.PreRelease = Identifiers{{Str: "beta"}, {Num: p.PreNum, IsNum: true}}
+	case p.Pre == "rc":
+		v.PreRelease = Identifiers{{Str: "rc"}, {Num: p.PreNum, IsNum: true}}
+	case p.HasDev:
+		v.PreRelease = Identifiers{{Str: "dev"}, {Num: p.Dev, IsNum: true}}
+	}
+
+	return v, nil
+}
+
+// IsPreRelease returns true if p is a pre-release or development release, false otherwise.
+func (p *PEP440Version) IsPreRelease() bool {
+	return p.Pre != "" || p.HasDev
+}
+
+// Public returns a copy of p without its local version label.
+func (p *PEP440Version) Public() *PEP440Version {
+	c := p.Clone()
+	c.Local = Identifiers{}
+	return c
+}
+
+// Clone returns a cloned copy.
+func (p *PEP440Version) Clone() *PEP440Version {
+	c := *p
+	c.Release = append([]uint64(nil), p.Release...)
+	c.Local = p.Local.Clone()
+	return &c
+}
+
+// String returns the normalized form of p.
+func (p *PEP440Version) String() string {
+	b := make([]byte, 0, 8)
+	if p.Epoch != 0 {
+		b = strconv.AppendUint(b, p.Epoch, 10)
+		b = append(b, '!')
+	}
+	for i, r := range p.Release {
+		if i > 0 {
+			b = append(b, '.')
+		}
+		b = strconv.AppendUint(b, r, 10)
+	}
+	if p.Pre != "" {
+		b = append(b, p.Pre...)
+		b = strconv.AppendUint(b, p.PreNum, 10)
+	}
+	if p.HasPost {
+		b = append(b, ".post"...)
+		b = strconv.AppendUint(b, p.Post, 10)
+	}
+	if p.HasDev {
+		b = append(b, ".dev"...)
+		b = strconv.AppendUint(b, p.Dev, 10)
+	}
+	if len(p.Local) > 0 {
+		b = append(b, '+')
+		b = append(b, p.Local.String()...)
+	}
+	return string(b)
+}
+
+// text returns the text p was parsed from, or its normalized form if p was
+// not parsed.
+func (p *PEP440Version) text() string {
+	if p.original != "" {
+		return p.original
+	}
+	return p.String()
+}
+
+// Compare tests if p is less than, equal to, or greater than o using the
+// PEP 440 ordering, returning -1, 0, or +1 respectively.
+func (p *PEP440Version) Compare(o *PEP440Version) int {
+	if c := compareUint(p.Epoch, o.Epoch); c != 0 {
+		return c
+	}
+	if c := comparePEP440Release(p.Release, o.Release); c != 0 {
+		return c
+	}
+	if c := compareInt(p.preRank(), o.preRank()); c != 0 {
+		return c
+	}
+	if p.Pre != "" && o.Pre != "" {
+		if c := compareUint(p.PreNum, o.PreNum); c != 0 {
+			return c
+		}
+	}
+	if p.HasPost != o.HasPost {
+		if p.HasPost {
+			return 1
+		}
+		return -1
+	}
+	if c := compareUint(p.Post, o.Post); c != 0 {
+		return c
+	}
+	if p.HasDev != o.HasDev {
+		if p.HasDev {
+			return -1
+		}
+		return 1
+	}
+	if c := compareUint(p.Dev, o.Dev); c != 0 {
+		return c
+	}
+	return comparePEP440Local(p.Local, o.Local)
+}
+
+// preRank orders the pre-release phase: a development release of the final
+// version sorts before any pre-release, and the final version after them.
+func (p *PEP440Version) preRank() int {
+	switch p.Pre {
+	case "a":
+		return 1
+	case "b":
+		return 2
+	case "rc":
+		return 3
+	}
+	if p.HasDev && !p.HasPost {
+		return 0
+	}
+	return 4
+}
+
+// PEP440Versions is an array of PEP440Version pointers for sorting.
+type PEP440Versions []*PEP440Version
+
+func (s PEP440Versions) Len() int {
+	return len(s)
+}
+
+func (s PEP440Versions) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s PEP440Versions) Less(i, j int) bool {
+	return s[i].Compare(s[j]) < 0
+}
+
+// PEP440Specifier is a single PEP 440 version specifier, such as "~=2.2" or "!=1.2.*".
+type PEP440Specifier struct {
+	Operator string
+	Version  string
+	wildcard bool
+	v        *PEP440Version
+}
+
+// PEP440SpecifierSet is a comma separated list of PEP 440 specifiers, all of which must match.
+type PEP440SpecifierSet struct {
+	Specifiers []PEP440Specifier
+	// PreReleases allows pre-releases to match even if none of the
+	// specifiers explicitly mention a pre-release.
+	PreReleases bool
+}
+
+var pep440Operators = []string{"===", "~=", "==", "!=", "<=", ">=", "<", ">"}
+
+// ParsePEP440Specifier parses a single PEP 440 version specifier.
+func ParsePEP440Specifier(s string) (PEP440Specifier, error) {
+	s = strings.TrimSpace(s)
+	var op string
+	for _, candidate := range pep440Operators {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return PEP440Specifier{}, fmt.Errorf("could not parse PEP 440 operator in %q", s)
+	}
+
+	spec := PEP440Specifier{Operator: op, Version: strings.TrimSpace(s[len(op):])}
+	if spec.Version == "" {
+		return PEP440Specifier{}, fmt.Errorf("missing version in PEP 440 specifier %q", s)
+	}
+	if op == "===" {
+		return spec, nil
+	}
+
+	vStr := spec.Version
+	if strings.HasSuffix(vStr, ".*") {
+		if op != "==" && op != "!=" {
+			return PEP440Specifier{}, fmt.Errorf("prefix match is not allowed with %q in %q", op, s)
+		}
+		spec.wildcard = true
+		vStr = strings.TrimSuffix(vStr, ".*")
+	}
+
+	v, err := NewPEP440Version(vStr)
+	if err != nil {
+		return PEP440Specifier{}, fmt.Errorf("could not parse version %q in %q: %s", vStr, s, err)
+	}
+	switch {
+	case spec.wildcard && (v.Pre != "" || v.HasPost || v.HasDev || len(v.Local) > 0):
+		return PEP440Specifier{}, fmt.Errorf("prefix match only supports release segments in %q", s)
+	case op == "~=" && len(v.Release) < 2:
+		return PEP440Specifier{}, fmt.Errorf("compatible release requires at least two release segments in %q", s)
+	case len(v.Local) > 0 && op != "==" && op != "!=":
+		return PEP440Specifier{}, fmt.Errorf("local version label is not allowed with %q in %q", op, s)
+	}
+	spec.v = v
+
+	return spec, nil
+}
+
+// ParsePEP440Specifiers parses a comma separated PEP 440 specifier set.
+// An empty string results in a set that matches every final release.
+func ParsePEP440Specifiers(s string) (*PEP440SpecifierSet, error) {
+	set := &PEP440SpecifierSet{}
+	if strings.TrimSpace(s) == "" {
+		return set, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		spec, err := ParsePEP440Specifier(part)
+		if err != nil {
+			return nil, err
+		}
+		set.Specifiers = append(set.Specifiers, spec)
+	}
+	return set, nil
+}
+
+// ParsePEP440Range parses a PEP 440 specifier set and returns a Range.
+// Versions are converted with PEP440FromVersion before being checked; a
+// Version without a PEP 440 equivalent never satisfies the Range.
+func ParsePEP440Range(s string) (Range, error) {
+	set, err := ParsePEP440Specifiers(s)
+	if err != nil {
+		return nil, err
+	}
+	return set.Range(), nil
+}
+
+// Contains checks if p satisfies the specifier, ignoring pre-release filtering.
+func (spec PEP440Specifier) Contains(p *PEP440Version) bool {
+	switch spec.Operator {
+	case "===":
+		return strings.EqualFold(spec.Version, p.text())
+	case "==":
+		return spec.equal(p)
+	case "!=":
+		return !spec.equal(p)
+	case "~=":
+		prefix := PEP440Specifier{v: &PEP440Version{Epoch: spec.v.Epoch, Release: spec.v.Release[:len(spec.v.Release)-1]}, wildcard: true}
+		return p.Public().Compare(spec.v) >= 0 && prefix.equal(p)
+	case "<=":
+		return p.Public().Compare(spec.v) <= 0
+	case ">=":
+		return p.Public().Compare(spec.v) >= 0
+	case "<":
+		if p.Public().Compare(spec.v) >= 0 {
+			return false
+		}
+		// Exclusive ordering excludes pre-releases of the given version
+		return spec.v.IsPreRelease() || !p.IsPreRelease() || !sameRelease(p, spec.v)
+	case ">":
+		if p.Public().Compare(spec.v) <= 0 {
+			return false
+		}
+		// Exclusive ordering excludes post-releases of the given version
+		return spec.v.HasPost || !p.HasPost || !sameRelease(p, spec.v)
+	}
+	return false
+}
+
+func (spec PEP440Specifier) equal(p *PEP440Version) bool {
+	if spec.wildcard {
+		if p.Epoch != spec.v.Epoch {
+			return false
+		}
+		for i, r := range spec.v.Release {
+			var other uint64
+			if i < len(p.Release) {
+				other = p.Release[i]
+			}
+			if r != other {
+				return false
+			}
+		}
+		return true
+	}
+	if len(spec.v.Local) == 0 {
+		p = p.Public()
+	}
+	return p.Compare(spec.v) == 0
+}
+
+func (spec PEP440Specifier) String() string {
+	return spec.Operator + spec.Version
+}
+
+// Contains checks if p satisfies every specifier in the set.
+// Pre-releases only match if the set allows them, or if any specifier
+// explicitly mentions a pre-release.
+func (set *PEP440SpecifierSet) Contains(p *PEP440Version) bool {
+	if p.IsPreRelease() && !set.allowsPreReleases() {
+		return false
+	}
+	for _, spec := range set.Specifiers {
+		if !spec.Contains(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *PEP440SpecifierSet) allowsPreReleases() bool {
+	if set.PreReleases {
+		return true
+	}
+	for _, spec := range set.Specifiers {
+		if spec.v != nil && spec.v.IsPreRelease() && spec.Operator != "!=" {
+			return true
+		}
+	}
+	return false
+}
+
+// Range returns a Range that converts each Version with PEP440FromVersion
+// and checks it against the set.
+func (set *PEP440SpecifierSet) Range() Range {
+	return func(v *Version) bool {
+		p, err := PEP440FromVersion(v)
+		if err != nil {
+			return false
+		}
+		return set.Contains(p)
+	}
+}
+
+func (set *PEP440SpecifierSet) String() string {
+	parts := make([]string, len(set.Specifiers))
+	for i, spec := range set.Specifiers {
+		parts[i] = spec.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func normalizePEP440PreLabel(l string) string {
+	switch strings.ToLower(l) {
+	case "alpha", "a":
+		return "a"
+	case "beta", "b":
+		return "b"
+	default:
+		return "rc"
+	}
+}
+
+func isPEP440Separator(r rune) bool {
+	return r == '-' || r == '_' || r == '.'
+}
+
+func parseOptionalUint(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+func sameRelease(p, o *PEP440Version) bool {
+	return p.Epoch == o.Epoch && comparePEP440Release(p.Release, o.Release) == 0
+}
+
+// comparePEP440Release compares release segments, ignoring trailing zeroes.
+func comparePEP440Release(r, o []uint64) int {
+	for i := 0; i < len(r) || i < len(o); i++ {
+		var a, b uint64
+		if i < len(r) {
+			a = r[i]
+		}
+		if i < len(o) {
+			b = o[i]
+		}
+		if c := compareUint(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// comparePEP440Local compares local version labels.  Unlike pre-release
+// identifiers, numeric segments sort after alphanumeric segments and a
+// version without a local label sorts before one with a label.
+func comparePEP440Local(l, o Identifiers) int {
+	for i := 0; i < len(l) && i < len(o); i++ {
+		a, b := l[i], o[i]
+		if a.IsNum != b.IsNum {
+			if a.IsNum {
+				return 1
+			}
+			return -1
+		}
+		if c := a.Compare(b); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(l), len(o))
+}
+
+func compareUint(a, b uint64) int {
+	if a == b {
+		return 0
+	} else if a > b {
+		return 1
+	}
+	return -1
+}
+
+func compareInt(a, b int) int {
+	if a == b {
+		return 0
+	} else if a > b {
+		return 1
+	}
+	return -1
+}
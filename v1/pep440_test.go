@@ -0,0 +1,304 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestNewPEP440Version(t *testing.T) {
+	tests := []struct {
+		input      string
+		normalized string
+	}{
+		{"1.0", "1.0"},
+		{"v1.0", "1.0"},
+		{"1!2.0", "1!2.0"},
+		{"1.0a1", "1.0a1"},
+		{"1.0.ALPHA.1", "1.0a1"},
+		{"1.0-beta2", "1.0b2"},
+		{"1.0c3", "1.0rc3"},
+		{"1.0preview", "1.0rc0"},
+		{"1.0-1", "1.0.post1"},
+		{"1.0.rev", "1.0.post0"},
+		{"1.0_post_2", "1.0.post2"},
+		{"1.0-dev3", "1.0.dev3"},
+		{"1.0a1.post2.dev3", "1.0a1.post2.dev3"},
+		{"1.0+Ubuntu-1", "1.0+ubuntu.1"},
+		{" 1.2.3.4 ", "1.2.3.4"},
+		{"", ""},
+		{"1.0+", ""},
+		{"1.0-gamma", ""},
+		{"a.b", ""},
+	}
+
+	Convey("Test PEP 440 parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.input, func() {
+				p, err := semver.NewPEP440Version(tc.input)
+				if tc.normalized == "" {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(p.String(), ShouldEqual, tc.normalized)
+				}
+			})
+		}
+	})
+}
+
+func TestPEP440Compare(t *testing.T) {
+	Convey("Test PEP 440 ordering", t, func() {
+		expected := []string{
+			"1.0.dev456",
+			"1.0a1",
+			"1.0a2.dev456",
+			"1.0a12.dev456",
+			"1.0a12",
+			"1.0b1.dev456",
+			"1.0b2",
+			"1.0b2.post345.dev456",
+			"1.0b2.post345",
+			"1.0rc1.dev456",
+			"1.0rc1",
+			"1.0",
+			"1.0+abc.5",
+			"1.0+abc.7",
+			"1.0+5",
+			"1.0.post456.dev34",
+			"1.0.post456",
+			"1.1.dev1",
+			"1!0.1",
+		}
+		data := make(semver.PEP440Versions, len(expected))
+		for i := range expected {
+			data[len(data)-1-i] = semver.MustPEP440(semver.NewPEP440Version(expected[i]))
+		}
+
+		sort.Sort(data)
+
+		for i, p := range data {
+			So(p.String(), ShouldEqual, expected[i])
+		}
+
+		Convey("trailing zeroes are insignificant", func() {
+			a := semver.MustPEP440(semver.NewPEP440Version("1.0"))
+			b := semver.MustPEP440(semver.NewPEP440Version("1.0.0"))
+			So(a.Compare(b), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestPEP440Specifiers(t *testing.T) {
+	type test struct {
+		v        string
+		expected bool
+	}
+	tests := []struct {
+		specifiers string
+		data       []test
+	}{
+		{"~=2.2", []test{
+			{"2.1", false},
+			{"2.2", true},
+			{"2.9.1", true},
+			{"3.0", false},
+			{"2.3a1", false},
+		}},
+		{"~=1.4.5", []test{
+			{"1.4.4", false},
+			{"1.4.5", true},
+			{"1.4.99", true},
+			{"1.5.0", false},
+		}},
+		{"==1.2.*", []test{
+			{"1.2", true},
+			{"1.2.7", true},
+			{"1.2.7+local", true},
+			{"1.3", false},
+		}},
+		{"!=1.2.*", []test{
+			{"1.1", true},
+			{"1.2.3", false},
+			{"1.3", true},
+		}},
+		{"==1.0", []test{
+			{"1.0.0", true},
+			{"1.0+local", true},
+			{"1.0.post1", false},
+		}},
+		{"==1.0+local", []test{
+			{"1.0", false},
+			{"1.0+local", true},
+		}},
+		{"===1.0.0", []test{
+			{"1.0", false},
+			{"1.0.0", true},
+			{"1.0.0.0", false},
+			{"v1.0.0", false},
+			{"1.0.0+local", false},
+		}},
+		{"===1.0", []test{
+			{"1.0", true},
+			{"1.0.0", false},
+			{"1.0.post0", false},
+		}},
+		{"===1.0.post1", []test{
+			{"1.0.POST1", true},
+			{"1.0-1", false},
+		}},
+		{">=1.0, <2.0, !=1.5.1", []test{
+			{"0.9", false},
+			{"1.0", true},
+			{"1.5.1", false},
+			{"1.9.9", true},
+			{"2.0", false},
+			{"2.0a1", false},
+		}},
+		{"<2.0", []test{
+			{"1.9", true},
+			{"2.0rc1", false},
+		}},
+		{"<2.0rc2", []test{
+			{"2.0rc1", true},
+		}},
+		{">1.7", []test{
+			{"1.7", false},
+			{"1.7.post1", false},
+			{"1.7.1", true},
+		}},
+		{">1.7.post2", []test{
+			{"1.7.post3", true},
+		}},
+		{">=1.0a1", []test{
+			{"1.0a2", true},
+			{"1.0", true},
+		}},
+		{"", []test{
+			{"1.0", true},
+			{"1.0a1", false},
+		}},
+		// Errors
+		{"1.0", nil},
+		{"~=1", nil},
+		{">=1.*", nil},
+		{"==1.0a1.*", nil},
+		{"<1.0+local", nil},
+		{">=", nil},
+		{">=1.0,", nil},
+	}
+
+	Convey("Test PEP 440 specifiers", t, func() {
+		for _, tc := range tests {
+			Convey(tc.specifiers, func() {
+				set, err := semver.ParsePEP440Specifiers(tc.specifiers)
+				if tc.data == nil {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					for _, td := range tc.data {
+						Convey(td.v, func() {
+							p := semver.MustPEP440(semver.NewPEP440Version(td.v))
+							So(set.Contains(p), ShouldEqual, td.expected)
+						})
+					}
+				}
+			})
+		}
+		Convey("explicitly allowing pre-releases", func() {
+			set, err := semver.ParsePEP440Specifiers(">=1.0")
+			So(err, ShouldBeNil)
+			set.PreReleases = true
+			So(set.Contains(semver.MustPEP440(semver.NewPEP440Version("1.1b1"))), ShouldBeTrue)
+		})
+	})
+}
+
+func TestPEP440Conversion(t *testing.T) {
+	tests := []struct {
+		pep440 string
+		semver string
+	}{
+		{"1", "1.0.0"},
+		{"1.2.3", "1.2.3"},
+		{"1.2.3a1", "1.2.3-alpha.1"},
+		{"1.2b2", "1.2.0-beta.2"},
+		{"1.2rc1", "1.2.0-rc.1"},
+		{"1.2.dev4", "1.2.0-dev.4"},
+		{"1.2+ubuntu.1", "1.2.0+ubuntu.1"},
+		{"1!1.2", ""},
+		{"1.2.3.4", ""},
+		{"1.2.post1", ""},
+		{"1.2a1.dev1", ""},
+	}
+
+	Convey("Test PEP 440 to Version", t, func() {
+		for _, tc := range tests {
+			Convey(tc.pep440, func() {
+				v, err := semver.MustPEP440(semver.NewPEP440Version(tc.pep440)).Version()
+				if tc.semver == "" {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(v.String(), ShouldEqual, tc.semver)
+				}
+			})
+		}
+	})
+
+	Convey("Test Version to PEP 440", t, func() {
+		for _, tc := range tests {
+			if tc.semver == "" {
+				continue
+			}
+			Convey(tc.semver, func() {
+				p, err := semver.PEP440FromVersion(semver.New(tc.semver))
+				So(err, ShouldBeNil)
+				So(p.Compare(semver.MustPEP440(semver.NewPEP440Version(tc.pep440))), ShouldEqual, 0)
+			})
+		}
+		Convey("unknown pre-release", func() {
+			_, err := semver.PEP440FromVersion(semver.New("1.0.0-snapshot"))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestParsePEP440Range(t *testing.T) {
+	Convey("Test PEP 440 Range", t, func() {
+		r, err := semver.ParsePEP440Range("~=1.4, !=1.5.2")
+		So(err, ShouldBeNil)
+		So(r(semver.New("1.4.0")), ShouldBeTrue)
+		So(r(semver.New("1.5.2")), ShouldBeFalse)
+		So(r(semver.New("1.9.0")), ShouldBeTrue)
+		So(r(semver.New("2.0.0")), ShouldBeFalse)
+		So(r(semver.New("1.6.0-snapshot")), ShouldBeFalse)
+
+		Convey("combines with other ranges", func() {
+			rf := r.AND(semver.MustParseRange("<1.6.0"))
+			So(rf(semver.New("1.5.0")), ShouldBeTrue)
+			So(rf(semver.New("1.7.0")), ShouldBeFalse)
+		})
+
+		_, err = semver.ParsePEP440Range("~=1")
+		So(err, ShouldNotBeNil)
+	})
+}
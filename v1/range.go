@@ -18,61 +18,81 @@ package semver
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-type wildcardType int
-
-const (
-	two = 2
-
-	noneWildcard  wildcardType = iota
-	majorWildcard wildcardType = 1
-	minorWildcard wildcardType = 2
-	patchWildcard wildcardType = 3
-)
-
-func wildcardTypeFromInt(i int) wildcardType {
-	switch i {
-	case int(majorWildcard):
-		return majorWildcard
-	case int(minorWildcard):
-		return minorWildcard
-	case int(patchWildcard):
-		return patchWildcard
-	default:
-		return noneWildcard
-	}
-}
-
 type comparator func(*Version, *Version) bool
 
+// comparison checks if the result of a three-way comparison, as returned by
+// the Compare methods in this package, satisfies an operator.  It allows
+// version types other than Version to share the operators of ParseRange.
+type comparison func(int) bool
+
 var (
-	compEQ comparator = func(v1 *Version, v2 *Version) bool {
-		return v1.Compare(v2) == 0
+	cmpEQ comparison = func(c int) bool {
+		return c == 0
 	}
-	compNE = func(v1 *Version, v2 *Version) bool {
-		return v1.Compare(v2) != 0
+	cmpNE comparison = func(c int) bool {
+		return c != 0
 	}
-	compGT = func(v1 *Version, v2 *Version) bool {
-		return v1.Compare(v2) == 1
+	cmpGT comparison = func(c int) bool {
+		return c > 0
 	}
-	compGE = func(v1 *Version, v2 *Version) bool {
-		return v1.Compare(v2) >= 0
+	cmpGE comparison = func(c int) bool {
+		return c >= 0
 	}
-	compLT = func(v1 *Version, v2 *Version) bool {
-		return v1.Compare(v2) == -1
+	cmpLT comparison = func(c int) bool {
+		return c < 0
 	}
-	compLE = func(v1 *Version, v2 *Version) bool {
-		return v1.Compare(v2) <= 0
+	cmpLE comparison = func(c int) bool {
+		return c <= 0
 	}
 )
 
+// comparator returns a comparator that compares two Versions using cmp.
+func (cmp comparison) comparator() comparator {
+	return func(v1 *Version, v2 *Version) bool {
+		return cmp(v1.Compare(v2))
+	}
+}
+
+var (
+	compEQ = cmpEQ.comparator()
+	compNE = cmpNE.comparator()
+	compGT = cmpGT.comparator()
+	compGE = cmpGE.comparator()
+	compLT = cmpLT.comparator()
+	compLE = cmpLE.comparator()
+)
+
 type versionRange struct {
 	v *Version
 	c comparator
+	// op is the canonical operator of c, as returned by canonicalOperator,
+	// if the versionRange was built from the syntax of ParseRange.
+	op string
+	// upper is set if vr excludes a partial version, such as "!=1.x", in
+	// which case the versions from v up to upper, exclusive, fail vr.
+	upper *Version
+	// partial is the partial version that vr excludes, if upper is set.
+	partial string
+}
+
+func (vr *versionRange) String() string {
+	if vr.upper != nil {
+		return vr.op + vr.partial
+	}
+	return vr.op + vr.v.String()
+}
+
+// excluding returns a comparator that is true if a version is lower than
+// the version it is compared with, or not lower than upper.
+func excluding(upper *Version) comparator {
+	return func(v, lower *Version) bool {
+		return v.LT(lower) || v.GE(upper)
+	}
 }
 
 // rangeFunc creates a Range from the given versionRange.
@@ -114,6 +134,25 @@ func (rf Range) AND(f Range) Range {
 //   - "1.0.0", "=1.0.0", "==1.0.0"
 //   - "!1.0.0", "!=1.0.0"
 //
+// A version may be partial or end with a wildcard "x", "X" or "*", in which
+// case it stands for every version with the given prefix:
+//   - "1.2", "1.2.x" would match ">=1.2.0 <1.3.0-0"
+//   - ">1", ">1.x" would match ">=2.0.0"
+//   - "<=1.2.x" would match "<1.3.0-0"
+//   - "!=1.x" would match "<1.0.0 || >=2.0.0-0"
+//
+// The pre-releases of the version above a partial version, such as
+// 1.3.0-beta for "1.2.x", do not have its prefix and so do not match it.
+//   - "*" would match any version, as would an empty range
+//
+// The caret and tilde operators match the versions that are compatible with
+// a version, as in Cargo and npm, without the pre-releases of the version
+// above them:
+//   - "^1.2.3", "^1.2" would match ">=1.2.3 <2.0.0-0" and ">=1.2.0 <2.0.0-0"
+//   - "^0.2.3" would match ">=0.2.3 <0.3.0-0" and "^0.0.3" would match ">=0.0.3 <0.0.4-0"
+//   - "~1.2.3", "~1.2" would match ">=1.2.3 <1.3.0-0" and ">=1.2.0 <1.3.0-0"
+//   - "~1" would match ">=1.0.0 <2.0.0-0"
+//
 // A Range can consist of multiple ranges separated by space:
 // Ranges can be linked by logical AND:
 //   - ">1.0.0 <2.0.0" would match between both ranges, so "1.1.1" and "1.8.7" but not "1.0.0" or "2.0.0"
@@ -127,291 +166,346 @@ func (rf Range) AND(f Range) Range {
 // Ranges can be combined by both AND and OR
 //
 //  - `>1.0.0 <2.0.0 || >3.0.0 !4.2.1` would match `1.2.3`, `1.9.9`, `3.1.1`, but not `4.2.1`, `2.1.1`
+//
+// As ranges may come from untrusted input, a range of more than 1024
+// comparators and '||' is rejected.
 func ParseRange(s string) (Range, error) {
-	parts := splitAndTrim(s)
-	orParts, err := splitORParts(parts)
+	c, err := ParseConstraint(s)
 	if err != nil {
 		return nil, err
 	}
-	expandedParts, err := expandWildcardVersion(orParts)
+	return c.Range(), nil
+}
+
+// satisfiesAll returns true if v satisfies every versionRange of group.
+func satisfiesAll(group []*versionRange, v *Version) bool {
+	for _, vr := range group {
+		if !vr.c(v, vr.v) {
+			return false
+		}
+	}
+	return true
+}
+
+// maxRangeTokens limits the comparators and '||' of a range, as compiling a
+// range to intervals takes time quadratic in the number of comparators.
+const maxRangeTokens = 1024
+
+// parseRangeGroups parses a range into OR'ed groups of AND'ed versionRanges,
+// with every wildcard and partial version expanded.  An empty range is a
+// single empty group, which matches any version.
+func parseRangeGroups(s string) ([][]*versionRange, error) {
+	tokenGroups, err := groupRangeTokens(s)
 	if err != nil {
 		return nil, err
 	}
-	var orFn Range
-	for _, p := range expandedParts {
-		var andFn Range
-		for _, ap := range p {
-			opStr, vStr, err := splitComparatorVersion(ap)
+
+	groups := make([][]*versionRange, len(tokenGroups))
+	for i, tokens := range tokenGroups {
+		for _, t := range tokens {
+			vrs, err := expandRangeTerm(t.op, t.version)
 			if err != nil {
 				return nil, err
 			}
-			vr, err := buildVersionRange(opStr, vStr)
-			if err != nil {
-				return nil, fmt.Errorf("could not parse Range %q: %expected", ap, err)
-			}
-			rf := vr.rangeFunc()
-
-			// Set function
-			if andFn == nil {
-				andFn = rf
-			} else { // Combine with existing function
-				andFn = andFn.AND(rf)
-			}
+			groups[i] = append(groups[i], vrs...)
 		}
-		if orFn == nil {
-			orFn = andFn
-		} else {
-			orFn = orFn.OR(andFn)
-		}
-
 	}
-	return orFn, nil
+	return groups, nil
 }
 
-// splitORParts splits the already cleaned parts by '||'.
-// Checks for invalid positions of the operator and returns an
-// error if found.
-func splitORParts(parts []string) ([][]string, error) {
-	var ORparts [][]string
-	last := 0
-	for i, p := range parts {
-		if p == "||" {
-			if i == 0 {
-				return nil, fmt.Errorf("first element in range is '||'")
-			}
-			ORparts = append(ORparts, parts[last:i])
-			last = i + 1
-		}
-	}
-	if last == len(parts) {
-		return nil, fmt.Errorf("last element in range is '||'")
+// groupRangeTokens splits a range into OR'ed groups of AND'ed comparator
+// tokens.  An empty range is a single empty group.  It is the parser of the
+// range syntax shared by ParseRange and the ranges of other version types.
+func groupRangeTokens(s string) ([][]rangeToken, error) {
+	tokens, err := tokenizeRange(s)
+	if err != nil {
+		return nil, err
 	}
-	ORparts = append(ORparts, parts[last:])
-	return ORparts, nil
-}
 
-// buildVersionRange takes a slice of 2: operator and version
-// and builds a versionRange, otherwise an error.
-func buildVersionRange(opStr, vStr string) (*versionRange, error) {
-	c := parseComparator(opStr)
-	if c == nil {
-		return nil, fmt.Errorf("could not parse comparator %q in %q", opStr, strings.Join([]string{opStr, vStr}, ""))
-	}
-	v, err := NewVersion(vStr)
-	if err != nil {
-		return nil, fmt.Errorf("could not parse version %q in %q: %expected", vStr, strings.Join([]string{opStr, vStr}, ""), err)
+	if n := len(tokens); n > maxRangeTokens {
+		return nil, fmt.Errorf("range has %d elements, more than the limit of %d", n, maxRangeTokens)
 	}
 
-	return &versionRange{
-		v: v,
-		c: c,
-	}, nil
+	var groups [][]rangeToken
+	var current []rangeToken
+	for i, t := range tokens {
+		if t.or {
+			switch {
+			case i == 0:
+				return nil, fmt.Errorf("first element in range is '||'")
+			case i == len(tokens)-1:
+				return nil, fmt.Errorf("last element in range is '||'")
+			case tokens[i-1].or:
+				return nil, fmt.Errorf("empty range between '||'")
+			}
+			groups = append(groups, current)
+			current = nil
+			continue
+		}
+		current = append(current, t)
+	}
 
+	return append(groups, current), nil
 }
 
-// inArray checks if a byte is contained in an array of bytes
-func inArray(s byte, list []byte) bool {
-	for _, el := range list {
-		if el == s {
-			return true
-		}
-	}
-	return false
+// rangeToken is either "||" or a comparator made of an operator, which may
+// be empty, and a version.
+type rangeToken struct {
+	or      bool
+	op      string
+	version string
 }
 
-// splitAndTrim splits a range string by spaces and cleans whitespaces
-func splitAndTrim(s string) (result []string) {
-	last := 0
-	var lastChar byte
-	excludeFromSplit := []byte{'>', '<', '='}
-	for i := 0; i < len(s); i++ {
-		if s[i] == ' ' && !inArray(lastChar, excludeFromSplit) {
-			if last < i-1 {
-				result = append(result, s[last:i])
+// tokenizeRange splits a range into rangeTokens.  Tokens are separated by
+// whitespace, which may also separate an operator from its version.
+func tokenizeRange(s string) ([]rangeToken, error) {
+	var tokens []rangeToken
+	for i := 0; i < len(s); {
+		c, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case strings.HasPrefix(s[i:], "||"):
+			tokens = append(tokens, rangeToken{or: true})
+			i += len("||")
+		case c == '|':
+			return nil, fmt.Errorf("unexpected '|' at offset %d in range %q", i, s)
+		default:
+			start := i
+			for i < len(s) && strings.IndexByte("<>=!^~", s[i]) != -1 {
+				i++
+			}
+			op := s[start:i]
+			i = skipSpace(s, i)
+
+			versionStart := i
+			for i < len(s) {
+				c, size := utf8.DecodeRuneInString(s[i:])
+				if unicode.IsSpace(c) || c == '|' {
+					break
+				}
+				i += size
+			}
+			if versionStart == i {
+				return nil, fmt.Errorf("missing version after %q in range %q", op, s)
 			}
-			last = i + 1
-		} else if s[i] != ' ' {
-			lastChar = s[i]
+			tokens = append(tokens, rangeToken{op: op, version: s[versionStart:i]})
 		}
 	}
-	if last < len(s)-1 {
-		result = append(result, s[last:])
-	}
+	return tokens, nil
+}
 
-	for i, v := range result {
-		result[i] = strings.Replace(v, " ", "", -1)
+// skipSpace returns the offset of the first non-whitespace character of s
+// at or after i.
+func skipSpace(s string, i int) int {
+	for i < len(s) {
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if !unicode.IsSpace(c) {
+			break
+		}
+		i += size
 	}
-
-	return
+	return i
 }
 
-// splitComparatorVersion splits the comparator from the version.
-// Input must be free of leading or trailing spaces.
-func splitComparatorVersion(s string) (string, string, error) {
-	i := strings.IndexFunc(s, unicode.IsDigit)
-	if i == -1 {
-		return "", "", fmt.Errorf("could not get version from string: %q", s)
-	}
-	return strings.TrimSpace(s[0:i]), s[i:], nil
+// lowestVersion returns 0.0.0-0, which precedes every other version.
+func lowestVersion() *Version {
+	return &Version{PreRelease: Identifiers{{Num: 0, IsNum: true}}, Metadata: Identifiers{}}
 }
 
-// getWildcardType will return the type of wildcard that the
-// passed version contains
-func getWildcardType(vStr string) wildcardType {
-	parts := strings.Split(vStr, ".")
-	n := len(parts)
-	wildcard := parts[n-1]
+// lowestPreRelease returns v-0, the lowest pre-release of the release v.
+func lowestPreRelease(v *Version) *Version {
+	return &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, PreRelease: Identifiers{{Num: 0, IsNum: true}}, Metadata: Identifiers{}}
+}
 
-	possibleWildcardType := wildcardTypeFromInt(n)
-	if wildcard == "x" {
-		return possibleWildcardType
+// expandRangeTerm expands a comparator of ParseRange into AND'ed
+// versionRanges, all of which must match.  A complete version is
+// compared as is, while a partial version, such as "1", "1.2", "1.x" or "*",
+// stands for every version with the given prefix:
+//
+//	1.2.x, =1.2     >=1.2.0 <1.3.0-0
+//	>=1.2.x         >=1.2.0
+//	>1.2.x          >=1.3.0
+//	<1.2.x          <1.2.0
+//	<=1.2.x         <1.3.0-0
+//	!=1.2.x         <1.2.0 || >=1.3.0-0
+//	^1.2.3          >=1.2.3 <2.0.0-0
+//	^0.2.3          >=0.2.3 <0.3.0-0
+//	~1.2.3          >=1.2.3 <1.3.0-0
+//
+// The upper bound of a partial version is the lowest pre-release of the next
+// version, as the pre-releases of 1.3.0 are not 1.2.x.
+// An excluded partial version is a single versionRange rather than two
+// alternatives, so that the branches of a range with many exclusions do not
+// multiply.
+// The empty prefix of "*" or "x" matches any version, so that "*" and ">=*"
+// match any version while ">*" and "!=*" match none.
+func expandRangeTerm(opStr, vStr string) ([]*versionRange, error) {
+	caretOrTilde := opStr == "^" || opStr == "~"
+	if !caretOrTilde && parseComparison(opStr) == nil {
+		return nil, fmt.Errorf("could not parse comparator %q in %q", opStr, opStr+vStr)
+	}
+	pv, err := parsePartialVersion(vStr)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse version %q in %q: %s", vStr, opStr+vStr, err)
+	}
+	if caretOrTilde && pv.wildcard {
+		// A wildcard already stands for the compatible versions.
+		opStr, caretOrTilde = "", false
+	}
+	if caretOrTilde {
+		// Caret and tilde ranges are those of Cargo, which are a lower
+		// bound followed by an upper bound.  As ParseRange matches
+		// pre-releases, the upper bound excludes those of the next
+		// version, such as 2.0.0-alpha for "^1.2.3".
+		vrs, err := cargoVersionRanges(opStr, pv)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %q: %s", opStr+vStr, err)
+		}
+		vrs[0].op = ">="
+		if len(vrs) > 1 {
+			vrs[1].v, vrs[1].op = lowestPreRelease(vrs[1].v), "<"
+		}
+		return vrs, nil
+	}
+	if pv.parts == versionComponents {
+		vr, err := buildVersionRange(opStr, vStr)
+		if err != nil {
+			return nil, err
+		}
+		return []*versionRange{vr}, nil
 	}
 
-	return noneWildcard
-}
-
-// createVersionFromWildcard will convert a wildcard version
-// into a regular version, replacing 'x'expected with '0'expected, handling
-// special cases like '1.x.x' and '1.x'
-func createVersionFromWildcard(vStr string) string {
-	// handle 1.x.x
-	vStr2 := strings.Replace(vStr, ".x.x", ".x", 1)
-	vStr2 = strings.Replace(vStr2, ".x", ".0", 1)
-	parts := strings.Split(vStr2, ".")
+	lower, next := pv.lower(), pv.next()
+	if pv.parts == 0 {
+		lower = lowestVersion()
+	}
+	matchAny := []*versionRange{{v: lowestVersion(), c: compGE, op: ">="}}
+	matchNone := []*versionRange{{v: lowestVersion(), c: compLT, op: "<"}}
+	if next == nil {
+		switch opStr {
+		case "", "=", "==", "<=":
+			return matchAny, nil
+		case ">":
+			return matchNone, nil
+		}
+	}
 
-	// handle 1.x
-	if len(parts) == two {
-		return vStr2 + ".0"
+	switch opStr {
+	case "", "=", "==":
+		return []*versionRange{{v: lower, c: compGE, op: ">="}, {v: lowestPreRelease(next), c: compLT, op: "<"}}, nil
+	case ">=":
+		return []*versionRange{{v: lower, c: compGE, op: ">="}}, nil
+	case ">":
+		return []*versionRange{{v: next, c: compGE, op: ">="}}, nil
+	case "<":
+		return []*versionRange{{v: lower, c: compLT, op: "<"}}, nil
+	case "<=":
+		return []*versionRange{{v: lowestPreRelease(next), c: compLT, op: "<"}}, nil
+	default: // "!=", "!"
+		if next == nil {
+			return []*versionRange{{v: lower, c: compLT, op: "<"}}, nil
+		}
+		upper := lowestPreRelease(next)
+		return []*versionRange{{v: lower, c: excluding(upper), op: "!=", upper: upper, partial: vStr}}, nil
 	}
+}
 
-	return vStr2
+// rangeTerm is a single operator and version pair of a range.
+type rangeTerm struct {
+	cmp     comparison
+	version string
 }
 
-// incrementMajorVersion will increment the major version
-// of the passed version
-func incrementMajorVersion(vStr string) (string, error) {
-	parts := strings.Split(vStr, ".")
-	i, err := strconv.Atoi(parts[0])
+// splitRangeTerms splits s into OR'ed groups of AND'ed rangeTerms, using the
+// syntax of ParseRange without wildcards.  parseOp parses the operators, which
+// allows version flavors to add their own spellings.  It is used to parse
+// ranges of version types other than Version, for which an empty range is
+// an error rather than a range that matches any version.
+func splitRangeTerms(s string, parseOp func(string) comparison) ([][]rangeTerm, error) {
+	groups, err := groupRangeTokens(s)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	parts[0] = strconv.Itoa(i + 1)
-
-	return strings.Join(parts, "."), nil
+	if len(groups) == 1 && len(groups[0]) == 0 {
+		return nil, fmt.Errorf("empty range %q", s)
+	}
+	terms := make([][]rangeTerm, len(groups))
+	for i, tokens := range groups {
+		for _, t := range tokens {
+			cmp := parseOp(t.op)
+			if cmp == nil {
+				return nil, fmt.Errorf("could not parse comparator %q in %q", t.op, t.op+t.version)
+			}
+			terms[i] = append(terms[i], rangeTerm{cmp: cmp, version: t.version})
+		}
+	}
+	return terms, nil
 }
 
-// incrementMajorVersion will increment the minor version
-// of the passed version
-func incrementMinorVersion(vStr string) (string, error) {
-	parts := strings.Split(vStr, ".")
-	i, err := strconv.Atoi(parts[1])
+// buildVersionRange takes a slice of 2: operator and version
+// and builds a versionRange, otherwise an error.
+func buildVersionRange(opStr, vStr string) (*versionRange, error) {
+	c := parseComparator(opStr)
+	if c == nil {
+		return nil, fmt.Errorf("could not parse comparator %q in %q", opStr, strings.Join([]string{opStr, vStr}, ""))
+	}
+	v, err := NewVersion(vStr)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("could not parse version %q in %q: %expected", vStr, strings.Join([]string{opStr, vStr}, ""), err)
 	}
-	parts[1] = strconv.Itoa(i + 1)
 
-	return strings.Join(parts, "."), nil
-}
-
-// expandWildcardVersion will expand wildcards inside versions
-// following these rules:
-//
-// * when dealing with patch wildcards:
-// >= 1.2.x    will become    >= 1.2.0
-// <= 1.2.x    will become    <  1.3.0
-// >  1.2.x    will become    >= 1.3.0
-// <  1.2.x    will become    <  1.2.0
-// != 1.2.x    will become    <  1.2.0 >= 1.3.0
-//
-// * when dealing with minor wildcards:
-// >= 1.x      will become    >= 1.0.0
-// <= 1.x      will become    <  2.0.0
-// >  1.x      will become    >= 2.0.0
-// <  1.0      will become    <  1.0.0
-// != 1.x      will become    <  1.0.0 >= 2.0.0
-//
-// * when dealing with wildcards without
-// version operator:
-// 1.2.x       will become    >= 1.2.0 < 1.3.0
-// 1.x         will become    >= 1.0.0 < 2.0.0
-func expandWildcardVersion(parts [][]string) ([][]string, error) {
-	var expandedParts [][]string
-	for _, p := range parts {
-		var newParts []string
-		for _, ap := range p {
-			if strings.Contains(ap, "x") {
-				opStr, vStr, err := splitComparatorVersion(ap)
-				if err != nil {
-					return nil, err
-				}
-
-				versionWildcardType := getWildcardType(vStr)
-				flatVersion := createVersionFromWildcard(vStr)
-
-				var resultOperator string
-				var shouldIncrementVersion bool
-				switch opStr {
-				case ">":
-					resultOperator = ">="
-					shouldIncrementVersion = true
-				case ">=":
-					resultOperator = ">="
-				case "<":
-					resultOperator = "<"
-				case "<=":
-					resultOperator = "<"
-					shouldIncrementVersion = true
-				case "", "=", "==":
-					newParts = append(newParts, ">="+flatVersion)
-					resultOperator = "<"
-					shouldIncrementVersion = true
-				case "!=", "!":
-					newParts = append(newParts, "<"+flatVersion)
-					resultOperator = ">="
-					shouldIncrementVersion = true
-				}
+	return &versionRange{
+		v:  v,
+		c:  c,
+		op: canonicalOperator(opStr),
+	}, nil
 
-				var resultVersion string
-				if shouldIncrementVersion {
-					switch versionWildcardType {
-					case patchWildcard:
-						resultVersion, _ = incrementMinorVersion(flatVersion)
-					case minorWildcard:
-						resultVersion, _ = incrementMajorVersion(flatVersion)
-					}
-				} else {
-					resultVersion = flatVersion
-				}
+}
 
-				ap = resultOperator + resultVersion
-			}
-			newParts = append(newParts, ap)
-		}
-		expandedParts = append(expandedParts, newParts)
+func parseComparator(s string) comparator {
+	cmp := parseComparison(s)
+	if cmp == nil {
+		return nil
 	}
-
-	return expandedParts, nil
+	return cmp.comparator()
 }
 
-func parseComparator(s string) comparator {
+// parseComparison parses the operators accepted by ParseRange.
+func parseComparison(s string) comparison {
 	switch s {
 	case "==", "", "=":
-		return compEQ
+		return cmpEQ
 	case ">":
-		return compGT
+		return cmpGT
 	case ">=":
-		return compGE
+		return cmpGE
 	case "<":
-		return compLT
+		return cmpLT
 	case "<=":
-		return compLE
+		return cmpLE
 	case "!", "!=":
-		return compNE
+		return cmpNE
 	}
 
 	return nil
 }
 
+// canonicalOperator returns the canonical spelling of an operator accepted
+// by ParseRange, or an empty string if it is not one.
+func canonicalOperator(s string) string {
+	switch s {
+	case "==", "", "=":
+		return "="
+	case "!", "!=":
+		return "!="
+	case ">", ">=", "<", "<=":
+		return s
+	}
+
+	return ""
+}
+
 // MustParseRange is like ParseRange but panics if the range cannot be parsed.
 func MustParseRange(s string) Range {
 	rf, err := ParseRange(s)
@@ -29,11 +29,6 @@ var (
 	v124 = New("1.2.4")
 )
 
-type wildcardTypeTest struct {
-	input        string
-	wildcardType wildcardType
-}
-
 type comparatorTest struct {
 	input      string
 	comparator func(comparator) bool
@@ -70,24 +65,6 @@ func TestParseComparator(t *testing.T) {
 	})
 }
 
-func TestGetWildcardType(t *testing.T) {
-	tests := []wildcardTypeTest{
-		{"x", majorWildcard},
-		{"1.x", minorWildcard},
-		{"1.2.x", patchWildcard},
-		{"fo.o.b.ar", noneWildcard},
-	}
-
-	Convey("Test wildcard types", t, func() {
-		for _, tc := range tests {
-			Convey(tc.input, func() {
-				o := getWildcardType(tc.input)
-				So(o, ShouldEqual, tc.wildcardType)
-			})
-		}
-	})
-}
-
 func testEQ(f comparator) bool {
 	return f(v122, v122) && !f(v122, v123)
 }
@@ -112,61 +89,6 @@ func testLE(f comparator) bool {
 	return f(v122, v123) && f(v123, v124) && !f(v123, v122)
 }
 
-func TestSplitAndTrim(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected []string
-	}{
-		{"1.2.3 1.2.3", []string{"1.2.3", "1.2.3"}},
-		{"     1.2.3     1.2.3     ", []string{"1.2.3", "1.2.3"}},       // Spaces
-		{"  >=   1.2.3   <=  1.2.3   ", []string{">=1.2.3", "<=1.2.3"}}, // Spaces between operator and version
-		{"1.2.3 || >=1.2.3 <1.2.3", []string{"1.2.3", "||", ">=1.2.3", "<1.2.3"}},
-		{"      1.2.3      ||     >=1.2.3     <1.2.3    ", []string{"1.2.3", "||", ">=1.2.3", "<1.2.3"}},
-	}
-
-	Convey("Test split and trim", t, func() {
-		for _, tc := range tests {
-			Convey(tc.input, func() {
-				p := splitAndTrim(tc.input)
-				So(p, ShouldResemble, tc.expected)
-			})
-		}
-	})
-}
-
-func TestSplitComparatorVersion(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected []string
-	}{
-		{">1.2.3", []string{">", "1.2.3"}},
-		{">=1.2.3", []string{">=", "1.2.3"}},
-		{"<1.2.3", []string{"<", "1.2.3"}},
-		{"<=1.2.3", []string{"<=", "1.2.3"}},
-		{"1.2.3", []string{"", "1.2.3"}},
-		{"=1.2.3", []string{"=", "1.2.3"}},
-		{"==1.2.3", []string{"==", "1.2.3"}},
-		{"!=1.2.3", []string{"!=", "1.2.3"}},
-		{"!1.2.3", []string{"!", "1.2.3"}},
-		{"error", nil},
-	}
-
-	Convey("Test split comparator version", t, func() {
-		for _, tc := range tests {
-			Convey(tc.input, func() {
-				op, v, err := splitComparatorVersion(tc.input)
-				if tc.expected == nil {
-					So(err, ShouldNotBeNil)
-				} else {
-					So(err, ShouldBeNil)
-					So(op, ShouldEqual, tc.expected[0])
-					So(v, ShouldEqual, tc.expected[1])
-				}
-			})
-		}
-	})
-}
-
 func TestBuildVersionRange(t *testing.T) {
 	tests := []struct {
 		op       string
@@ -203,127 +125,184 @@ func TestBuildVersionRange(t *testing.T) {
 	})
 }
 
-func TestSplitORParts(t *testing.T) {
+func TestGroupRangeTokens(t *testing.T) {
 	tests := []struct {
-		input    []string
-		expected [][]string
+		input    string
+		expected [][]rangeToken
 	}{
-		{[]string{">1.2.3", "||", "<1.2.3", "||", "=1.2.3"}, [][]string{
-			{">1.2.3"},
-			{"<1.2.3"},
-			{"=1.2.3"},
+		{"", [][]rangeToken{nil}},
+		{">1.2.3 || <1.2.3 || =1.2.3", [][]rangeToken{
+			{{op: ">", version: "1.2.3"}},
+			{{op: "<", version: "1.2.3"}},
+			{{op: "=", version: "1.2.3"}},
 		}},
-		{[]string{">1.2.3", "<1.2.3", "||", "=1.2.3"}, [][]string{
-			{">1.2.3", "<1.2.3"},
-			{"=1.2.3"},
+		{">1.2.3 <1.2.3 || =1.2.3", [][]rangeToken{
+			{{op: ">", version: "1.2.3"}, {op: "<", version: "1.2.3"}},
+			{{op: "=", version: "1.2.3"}},
 		}},
-		{[]string{">1.2.3", "||"}, nil},
-		{[]string{"||", ">1.2.3"}, nil},
+		{">1.2.3 ||", nil},
+		{"|| >1.2.3", nil},
+		{">1.2.3 || || <1.2.3", nil},
 	}
 
-	Convey("Test split or conditionals", t, func() {
+	Convey("Test group range tokens", t, func() {
 		for _, tc := range tests {
-			Convey(strings.Join(tc.input, " "), func() {
-				o, err := splitORParts(tc.input)
+			Convey(tc.input, func() {
+				groups, err := groupRangeTokens(tc.input)
 				if tc.expected == nil {
 					So(err, ShouldNotBeNil)
 				} else {
 					So(err, ShouldBeNil)
-					So(o, ShouldResemble, tc.expected)
+					So(groups, ShouldResemble, tc.expected)
 				}
 			})
 		}
 	})
 }
 
-func TestCreateVersionFromWildcard(t *testing.T) {
+func TestTokenizeRange(t *testing.T) {
 	tests := []struct {
 		input    string
-		expected string
+		expected []rangeToken
 	}{
-		{"1.2.x", "1.2.0"},
-		{"1.x", "1.0.0"},
+		{"", nil},
+		{" \t\n", nil},
+		{"1.2.3", []rangeToken{{version: "1.2.3"}}},
+		{">= 1.2 <2", []rangeToken{{op: ">=", version: "1.2"}, {op: "<", version: "2"}}},
+		{"1.x||\t*", []rangeToken{{version: "1.x"}, {or: true}, {version: "*"}}},
+		{"!=1.0.0-x", []rangeToken{{op: "!=", version: "1.0.0-x"}}},
+		{"  >=   1.2.3   <=  1.2.3   ", []rangeToken{{op: ">=", version: "1.2.3"}, {op: "<=", version: "1.2.3"}}},
+		{"\t>=\t1.2.3\n<\r\n1.2.3\t", []rangeToken{{op: ">=", version: "1.2.3"}, {op: "<", version: "1.2.3"}}},
+		{">=1.2.3\u00a0<1.2.3", []rangeToken{{op: ">=", version: "1.2.3"}, {op: "<", version: "1.2.3"}}},
+		{"<< 1:2.0~rc1", []rangeToken{{op: "<<", version: "1:2.0~rc1"}}},
+		{"1.2.3 | 2.0.0", nil},
+		{">=1.2.3 <", nil},
 	}
 
-	Convey("Creating version from wildcard", t, func() {
+	Convey("Test tokenize range", t, func() {
 		for _, tc := range tests {
 			Convey(tc.input, func() {
-				v := createVersionFromWildcard(tc.input)
-				So(v, ShouldEqual, tc.expected)
+				tokens, err := tokenizeRange(tc.input)
+				if tc.expected == nil && strings.TrimSpace(tc.input) != "" {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(tokens, ShouldResemble, tc.expected)
+				}
 			})
 		}
 	})
 }
 
-func TestIncrementMajorVersion(t *testing.T) {
+type expandedTerm struct {
+	c func(comparator) bool
+	v string
+}
+
+func TestExpandRangeTerm(t *testing.T) {
 	tests := []struct {
-		input    string
-		expected string
+		op, v    string
+		expected []expandedTerm
 	}{
-		{"1.2.3", "2.2.3"},
-		{"1.2", "2.2"},
-		{"foo.bar", ""},
+		{"", "1.2.3", []expandedTerm{{testEQ, "1.2.3"}}},
+		{"", "1.0.0-x", []expandedTerm{{testEQ, "1.0.0-x"}}},
+		{"!=", "1.0.0-X", []expandedTerm{{testNE, "1.0.0-X"}}},
+		{"", "1.2.x", []expandedTerm{{testGE, "1.2.0"}, {testLT, "1.3.0-0"}}},
+		{"=", "1.2", []expandedTerm{{testGE, "1.2.0"}, {testLT, "1.3.0-0"}}},
+		{"==", "1.X.X", []expandedTerm{{testGE, "1.0.0"}, {testLT, "2.0.0-0"}}},
+		{">=", "1.2.x", []expandedTerm{{testGE, "1.2.0"}}},
+		{">", "1.2.*", []expandedTerm{{testGE, "1.3.0"}}},
+		{"<", "1.2", []expandedTerm{{testLT, "1.2.0"}}},
+		{"<=", "1.2.x", []expandedTerm{{testLT, "1.3.0-0"}}},
+		{">=", "1", []expandedTerm{{testGE, "1.0.0"}}},
+		{">", "1.x", []expandedTerm{{testGE, "2.0.0"}}},
+		{"<=", "1", []expandedTerm{{testLT, "2.0.0-0"}}},
+		{"", "*", []expandedTerm{{testGE, "0.0.0-0"}}},
+		{"", "x", []expandedTerm{{testGE, "0.0.0-0"}}},
+		{">=", "X", []expandedTerm{{testGE, "0.0.0-0"}}},
+		{"<=", "*", []expandedTerm{{testGE, "0.0.0-0"}}},
+		{">", "*", []expandedTerm{{testLT, "0.0.0-0"}}},
+		{"<", "*", []expandedTerm{{testLT, "0.0.0-0"}}},
+		{"!=", "*", []expandedTerm{{testLT, "0.0.0-0"}}},
+		{">>", "1.2.3", nil},
+		{"=", "foox", nil},
+		{"", "1.x.3", nil},
+		{"", "1.2-beta", nil},
+		{"", "1.2.3.4", nil},
+		{"", "01.2", nil},
 	}
 
-	Convey("Test increment major version", t, func() {
+	Convey("Test expand range term", t, func() {
 		for _, tc := range tests {
-			Convey(tc.input, func() {
-				v, _ := incrementMajorVersion(tc.input)
-				So(v, ShouldEqual, tc.expected)
+			Convey(tc.op+tc.v, func() {
+				vrs, err := expandRangeTerm(tc.op, tc.v)
+				if tc.expected == nil {
+					So(err, ShouldNotBeNil)
+					return
+				}
+				So(err, ShouldBeNil)
+				So(vrs, ShouldHaveLength, len(tc.expected))
+				for i, vr := range vrs {
+					So(vr.v.String(), ShouldEqual, tc.expected[i].v)
+					So(vr.upper, ShouldBeNil)
+					So(tc.expected[i].c(vr.c), ShouldBeTrue)
+				}
 			})
 		}
 	})
-}
 
-func TestIncrementMinorVersion(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected string
-	}{
-		{"1.2.3", "1.3.3"},
-		{"1.2", "1.3"},
-		{"foo.bar", ""},
-	}
-
-	Convey("Test increment minor version", t, func() {
-		for _, tc := range tests {
-			Convey(tc.input, func() {
-				p, _ := incrementMinorVersion(tc.input)
-				So(p, ShouldEqual, tc.expected)
+	Convey("Test expand excluded partial versions", t, func() {
+		for _, tc := range []struct {
+			op, v, lower, upper string
+		}{
+			{"!=", "1.2.x", "1.2.0", "1.3.0"},
+			{"!", "1", "1.0.0", "2.0.0"},
+		} {
+			Convey(tc.op+tc.v, func() {
+				vrs, err := expandRangeTerm(tc.op, tc.v)
+				So(err, ShouldBeNil)
+				So(vrs, ShouldHaveLength, 1)
+				vr := vrs[0]
+				So(vr.String(), ShouldEqual, "!="+tc.v)
+				So(vr.v.String(), ShouldEqual, tc.lower)
+				So(vr.upper.String(), ShouldEqual, tc.upper+"-0")
+				So(vr.c(New("0.9.0"), vr.v), ShouldBeTrue)
+				So(vr.c(New(tc.lower), vr.v), ShouldBeFalse)
+				So(vr.c(New(tc.upper+"-rc.1"), vr.v), ShouldBeTrue)
+				So(vr.c(New(tc.upper), vr.v), ShouldBeTrue)
 			})
 		}
 	})
 }
 
-func TestExpandWildcardVersion(t *testing.T) {
+func TestParseRangeGroups(t *testing.T) {
 	tests := []struct {
-		i [][]string
-		o [][]string
+		input    string
+		expected []int
 	}{
-		{[][]string{{"foox"}}, nil},
-		{[][]string{{">=1.2.x"}}, [][]string{{">=1.2.0"}}},
-		{[][]string{{"<=1.2.x"}}, [][]string{{"<1.3.0"}}},
-		{[][]string{{">1.2.x"}}, [][]string{{">=1.3.0"}}},
-		{[][]string{{"<1.2.x"}}, [][]string{{"<1.2.0"}}},
-		{[][]string{{"!=1.2.x"}}, [][]string{{"<1.2.0", ">=1.3.0"}}},
-		{[][]string{{">=1.x"}}, [][]string{{">=1.0.0"}}},
-		{[][]string{{"<=1.x"}}, [][]string{{"<2.0.0"}}},
-		{[][]string{{">1.x"}}, [][]string{{">=2.0.0"}}},
-		{[][]string{{"<1.x"}}, [][]string{{"<1.0.0"}}},
-		{[][]string{{"!=1.x"}}, [][]string{{"<1.0.0", ">=2.0.0"}}},
-		{[][]string{{"1.2.x"}}, [][]string{{">=1.2.0", "<1.3.0"}}},
-		{[][]string{{"1.x"}}, [][]string{{">=1.0.0", "<2.0.0"}}},
+		{"", []int{0}},
+		{"1.2.3", []int{1}},
+		{">=1.0.0 1.x", []int{3}},
+		{">=0.5.0 !=1.x", []int{2}},
+		{"!=1.x !=3.x || 5.0.0", []int{2, 1}},
+		{"|| 1.2.3", nil},
+		{"1.2.3 ||", nil},
+		{"1.2.3 || || 2.0.0", nil},
 	}
 
-	Convey("Test expand wildcard version", t, func() {
+	Convey("Test parse range groups", t, func() {
 		for _, tc := range tests {
-			Convey(strings.Join(tc.i[0], " "), func() {
-				o, err := expandWildcardVersion(tc.i)
-				if tc.o == nil {
+			Convey(tc.input, func() {
+				groups, err := parseRangeGroups(tc.input)
+				if tc.expected == nil {
 					So(err, ShouldNotBeNil)
 				} else {
 					So(err, ShouldBeNil)
-					So(o, ShouldResemble, tc.o)
+					lengths := make([]int, len(groups))
+					for i, g := range groups {
+						lengths[i] = len(g)
+					}
+					So(lengths, ShouldResemble, tc.expected)
 				}
 			})
 		}
@@ -17,7 +17,10 @@
 package semver_test
 
 import (
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 	"l7e.io/semver/v1"
@@ -129,11 +132,16 @@ func TestParseRange(t *testing.T) {
 		}},
 		// Simple Expression errors
 		{">>1.2.3", nil},
+		{"^>=1.2.3", nil},
+		{"~~1.2.3", nil},
+		{"^", nil},
 		{"!!1.2.3", nil},
-		{"1.0", nil},
 		{"string", nil},
-		{"", nil},
 		{"fo.ob.ar.x", nil},
+		{"1.x.3", nil},
+		{"1.2-beta", nil},
+		{"1.2.3 | 2.0.0", nil},
+		{">=", nil},
 		// AND Expressions
 		{">1.2.2 <1.2.4", []test{
 			{"1.2.2", false},
@@ -181,6 +189,152 @@ func TestParseRange(t *testing.T) {
 			{"1.2.6", false},
 			{"1.3.0", true},
 		}},
+		{"1.2.X", []test{
+			{"1.1.9", false},
+			{"1.2.0", true},
+			{"1.2.9", true},
+			{"1.3.0-alpha", false},
+			{"1.3.0", false},
+		}},
+		{"1.*", []test{
+			{"0.9.9", false},
+			{"1.0.0", true},
+			{"1.9.9", true},
+			{"2.0.0", false},
+		}},
+		{"1.x", []test{
+			{"1.0.0-rc.1", false},
+			{"1.9.9", true},
+			{"2.0.0-rc.1", false},
+		}},
+		{"!=1.x", []test{
+			{"0.9.9", true},
+			{"1.0.0", false},
+			{"1.9.9", false},
+			{"2.0.0-rc.1", true},
+			{"2.0.0", true},
+		}},
+		{">=0.5.0 !=1.X <3.0.0", []test{
+			{"0.4.0", false},
+			{"0.5.0", true},
+			{"1.5.0", false},
+			{"2.5.0", true},
+			{"3.0.0", false},
+		}},
+		{"^2.1", []test{
+			{"2.0.9", false},
+			{"2.1.0", true},
+			{"2.9.9", true},
+			{"3.0.0-beta.1", false},
+			{"3.0.0", false},
+		}},
+		{"^1.2.3", []test{
+			{"1.2.3", true},
+			{"1.9.0-rc.1", true},
+			{"2.0.0-alpha", false},
+		}},
+		{"^1.2.3-beta.2", []test{
+			{"1.2.3-beta.1", false},
+			{"1.2.3-beta.2", true},
+			{"1.9.0", true},
+			{"2.0.0", false},
+		}},
+		{"^0.2.3", []test{
+			{"0.2.2", false},
+			{"0.2.9", true},
+			{"0.3.0", false},
+		}},
+		{"^0.0.3", []test{
+			{"0.0.3", true},
+			{"0.0.4", false},
+		}},
+		{"^1.x", []test{
+			{"0.9.0", false},
+			{"1.9.0", true},
+			{"2.0.0", false},
+		}},
+		{"~1.2.3", []test{
+			{"1.2.2", false},
+			{"1.2.9", true},
+			{"1.3.0", false},
+		}},
+		{"~2.1", []test{
+			{"2.1.0", true},
+			{"2.2.0-rc.1", false},
+		}},
+		{"~ 1", []test{
+			{"1.9.0", true},
+			{"2.0.0", false},
+		}},
+		{"~1.2 || ^3", []test{
+			{"1.2.5", true},
+			{"2.0.0", false},
+			{"3.5.0", true},
+		}},
+		{"*", []test{
+			{"0.0.0-0", true},
+			{"0.0.0", true},
+			{"1.2.3-beta.1", true},
+			{"99.0.0", true},
+		}},
+		{"", []test{
+			{"0.0.0-0", true},
+			{"1.2.3", true},
+		}},
+		{" \t ", []test{
+			{"1.2.3", true},
+		}},
+		{">*", []test{
+			{"0.0.0-0", false},
+			{"1.2.3", false},
+		}},
+		{"!=*", []test{
+			{"1.2.3", false},
+		}},
+		// Partial versions
+		{"1.2", []test{
+			{"1.1.9", false},
+			{"1.2.0", true},
+			{"1.2.9", true},
+			{"1.3.0-alpha", false},
+			{"1.3.0", false},
+		}},
+		{">=1", []test{
+			{"0.9.9", false},
+			{"1.0.0", true},
+			{"2.0.0", true},
+		}},
+		{">1", []test{
+			{"1.9.9", false},
+			{"2.0.0", true},
+		}},
+		{"<1.2", []test{
+			{"1.1.9", true},
+			{"1.2.0-beta", true},
+			{"1.2.0", false},
+		}},
+		{"<=1.2", []test{
+			{"1.2.9", true},
+			{"1.3.0-beta", false},
+			{"1.3.0", false},
+		}},
+		{"!1", []test{
+			{"0.9.9", true},
+			{"1.5.0", false},
+			{"2.0.0", true},
+		}},
+		// Pre-releases containing an x are not wildcards
+		{"1.0.0-x", []test{
+			{"1.0.0", false},
+			{"1.0.0-x", true},
+			{"1.0.0-y", false},
+		}},
+		{">=1.0.0-x.1 <1.0.0", []test{
+			{"1.0.0-x.0", false},
+			{"1.0.0-x.1", true},
+			{"1.0.0-x.2", true},
+			{"1.0.0", false},
+		}},
 		// Combined Expressions
 		{">1.2.2 <1.2.4 || >=2.0.0", []test{
 			{"1.2.2", false},
@@ -226,6 +380,33 @@ func TestParseRange(t *testing.T) {
 	})
 }
 
+// exclusions returns a range that excludes the major versions 1 to n.
+func exclusions(n int) string {
+	terms := make([]string, n)
+	for i := range terms {
+		terms[i] = "!=" + strconv.Itoa(i+1)
+	}
+	return strings.Join(terms, " ")
+}
+
+func TestParseRangeExclusions(t *testing.T) {
+	Convey("Test parsing many excluded partial versions", t, func() {
+		start := time.Now()
+		rf, err := semver.ParseRange(exclusions(1000))
+		So(err, ShouldBeNil)
+		So(time.Since(start), ShouldBeLessThan, time.Second)
+
+		So(rf(semver.New("0.9.0")), ShouldBeTrue)
+		So(rf(semver.New("1.0.0")), ShouldBeFalse)
+		So(rf(semver.New("500.2.3")), ShouldBeFalse)
+		So(rf(semver.New("1000.9.9")), ShouldBeFalse)
+		So(rf(semver.New("1001.0.0")), ShouldBeTrue)
+
+		_, err = semver.ParseRange(exclusions(1025))
+		So(err, ShouldNotBeNil)
+	})
+}
+
 func TestMustParseRange(t *testing.T) {
 	Convey("Test MustParseRange", t, func() {
 		rf := semver.MustParseRange(">1.2.2 <1.2.4 || >=2.0.0 <3.0.0")
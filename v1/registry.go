@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry tracks the available versions of components, keyed by name, and
+// answers queries such as the latest stable version, the latest version in
+// a Range or the latest version of a pre-release channel.  It is safe for
+// concurrent use.
+//
+// A version is registered once per component, so that versions that differ
+// only in build metadata are the same.  A yanked version remains listed by
+// Versions but is no longer returned by queries.  The channels of queries
+// are the Stability of versions, as returned by StabilityOf.
+type Registry struct {
+	mu         sync.RWMutex
+	components map[string][]registryEntry
+}
+
+// registryEntry is a registered version, kept sorted by version.
+type registryEntry struct {
+	v      *Version
+	yanked bool
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{components: map[string][]registryEntry{}}
+}
+
+// search returns the index of the first entry of component that is not
+// lower than v, and whether it has the same precedence as v.
+func (r *Registry) search(component string, v *Version) (int, bool) {
+	entries := r.components[component]
+	i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].v.GE(v)
+	})
+	return i, i < len(entries) && entries[i].v.EQ(v)
+}
+
+// Add registers v as a version of component.  It returns false if the
+// version was already registered.
+func (r *Registry) Add(component string, v *Version) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, found := r.search(component, v)
+	if found {
+		return false
+	}
+	entries := append(r.components[component], registryEntry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = registryEntry{v: v.Clone()}
+	r.components[component] = entries
+	return true
+}
+
+// Remove removes v from the versions of component.  It returns false if
+// the version was not registered.
+func (r *Registry) Remove(component string, v *Version) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, found := r.search(component, v)
+	if !found {
+		return false
+	}
+	entries := r.components[component]
+	if len(entries) == 1 {
+		delete(r.components, component)
+		return true
+	}
+	r.components[component] = append(entries[:i], entries[i+1:]...)
+	return true
+}
+
+// Yank marks v of component as yanked, or unmarks it if yanked is false.
+// It returns false if the version was not registered.
+func (r *Registry) Yank(component string, v *Version, yanked bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, found := r.search(component, v)
+	if found {
+		r.components[component][i].yanked = yanked
+	}
+	return found
+}
+
+// IsYanked returns true if v of component is registered and yanked.
+func (r *Registry) IsYanked(component string, v *Version) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, found := r.search(component, v)
+	return found && r.components[component][i].yanked
+}
+
+// Components returns the names of the components with registered versions,
+// in lexical order.
+func (r *Registry) Components() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.components))
+	for name := range r.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns every registered version of component, including the
+// yanked ones, from lowest to highest.
+func (r *Registry) Versions(component string) Versions {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := r.components[component]
+	versions := make(Versions, len(entries))
+	for i, e := range entries {
+		versions[i] = e.v.Clone()
+	}
+	return versions
+}
+
+// Query returns the versions of component that are not yanked, satisfy rf
+// and are at least as stable as min, from lowest to highest.  A nil rf
+// matches any version.
+func (r *Registry) Query(component string, rf Range, min Stability) Versions {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := Versions{}
+	for _, e := range r.components[component] {
+		if e.matches(rf, min) {
+			versions = append(versions, e.v.Clone())
+		}
+	}
+	return versions
+}
+
+// Latest returns the highest version of component that is not yanked,
+// satisfies rf and is at least as stable as min, or nil if there is none.
+// A nil rf matches any version, so that the latest stable version is
+//
+//	r.Latest("api", nil, StabilityStable)
+//
+// and the latest version of the beta channel, which also includes release
+// candidates and stable versions, is
+//
+//	r.Latest("api", MustParseRange("2.x"), StabilityBeta)
+//
+// A channel is a minimum stability rather than an exact one, as users of a
+// channel should not be left behind when a release supersedes its
+// pre-releases: once 2.1.0 is released after 2.1.0-beta.2, the beta channel
+// returns 2.1.0 until a newer beta, rc or release is added.  Use Query and
+// StabilityOf to find the versions of exactly one stability.
+func (r *Registry) Latest(component string, rf Range, min Stability) *Version {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := r.components[component]
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].matches(rf, min) {
+			return entries[i].v.Clone()
+		}
+	}
+	return nil
+}
+
+func (e registryEntry) matches(rf Range, min Stability) bool {
+	return !e.yanked && StabilityOf(e.v) >= min && (rf == nil || rf(e.v))
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestRegistry(t *testing.T) {
+	Convey("Test registry", t, func() {
+		r := semver.NewRegistry()
+		for _, s := range []string{"2.1.0-beta.1", "1.0.0", "2.0.0", "1.1.0", "2.1.0-rc.1", "3.0.0-alpha.1", "2.0.1"} {
+			So(r.Add("api", semver.New(s)), ShouldBeTrue)
+		}
+		So(r.Add("web", semver.New("0.1.0")), ShouldBeTrue)
+
+		Convey("Versions are kept sorted", func() {
+			So(r.Versions("api"), ShouldResemble, semver.Versions{
+				semver.New("1.0.0"), semver.New("1.1.0"), semver.New("2.0.0"), semver.New("2.0.1"),
+				semver.New("2.1.0-beta.1"), semver.New("2.1.0-rc.1"), semver.New("3.0.0-alpha.1"),
+			})
+			So(r.Components(), ShouldResemble, []string{"api", "web"})
+			So(r.Versions("db"), ShouldBeEmpty)
+		})
+
+		Convey("Channels include more stable versions", func() {
+			So(r.Add("api", semver.New("2.1.0")), ShouldBeTrue)
+			So(r.Latest("api", nil, semver.StabilityBeta), ShouldResemble, semver.New("2.1.0"))
+			So(r.Latest("api", nil, semver.StabilityRC), ShouldResemble, semver.New("2.1.0"))
+			So(r.Latest("api", nil, semver.StabilityAlpha), ShouldResemble, semver.New("3.0.0-alpha.1"))
+			So(r.Query("api", semver.MustParseRange(">=2.1.0-0 <2.2.0"), semver.StabilityBeta), ShouldResemble, semver.Versions{
+				semver.New("2.1.0-beta.1"), semver.New("2.1.0-rc.1"), semver.New("2.1.0"),
+			})
+		})
+
+		Convey("Versions are registered once", func() {
+			So(r.Add("api", semver.New("2.0.0+build.5")), ShouldBeFalse)
+			So(r.Versions("api"), ShouldHaveLength, 7)
+		})
+
+		Convey("Latest versions by range and channel", func() {
+			So(r.Latest("api", nil, semver.StabilityStable), ShouldResemble, semver.New("2.0.1"))
+			So(r.Latest("api", nil, semver.StabilityBeta), ShouldResemble, semver.New("2.1.0-rc.1"))
+			So(r.Latest("api", nil, semver.StabilityAlpha), ShouldResemble, semver.New("3.0.0-alpha.1"))
+			So(r.Latest("api", semver.MustParseRange("1.x"), semver.StabilityStable), ShouldResemble, semver.New("1.1.0"))
+			So(r.Latest("api", semver.MustParseRange("<2.0.0 || >=2.1.0-beta <2.2.0"), semver.StabilityBeta), ShouldResemble, semver.New("2.1.0-rc.1"))
+			So(r.Latest("api", semver.MustParseRange(">=4"), semver.StabilityDev), ShouldBeNil)
+			So(r.Latest("db", nil, semver.StabilityDev), ShouldBeNil)
+			So(r.Query("api", semver.MustParseRange("2.x"), semver.StabilityStable), ShouldResemble, semver.Versions{
+				semver.New("2.0.0"), semver.New("2.0.1"),
+			})
+		})
+
+		Convey("Yanked versions are not returned by queries", func() {
+			So(r.Yank("api", semver.New("2.0.1"), true), ShouldBeTrue)
+			So(r.IsYanked("api", semver.New("2.0.1")), ShouldBeTrue)
+			So(r.Latest("api", nil, semver.StabilityStable), ShouldResemble, semver.New("2.0.0"))
+			So(r.Versions("api"), ShouldContain, semver.New("2.0.1"))
+
+			So(r.Yank("api", semver.New("2.0.1"), false), ShouldBeTrue)
+			So(r.Latest("api", nil, semver.StabilityStable), ShouldResemble, semver.New("2.0.1"))
+			So(r.Yank("api", semver.New("9.9.9"), true), ShouldBeFalse)
+		})
+
+		Convey("Removed versions are forgotten", func() {
+			So(r.Remove("api", semver.New("2.0.1")), ShouldBeTrue)
+			So(r.Remove("api", semver.New("2.0.1")), ShouldBeFalse)
+			So(r.Latest("api", nil, semver.StabilityStable), ShouldResemble, semver.New("2.0.0"))
+			So(r.Remove("web", semver.New("0.1.0")), ShouldBeTrue)
+			So(r.Components(), ShouldResemble, []string{"api"})
+		})
+
+		Convey("Returned versions are copies", func() {
+			r.Latest("api", nil, semver.StabilityStable).Major = 9
+			r.Versions("api")[0].Major = 9
+			So(r.Latest("api", nil, semver.StabilityStable), ShouldResemble, semver.New("2.0.1"))
+			So(r.Versions("api")[0], ShouldResemble, semver.New("1.0.0"))
+		})
+	})
+}
+
+func TestRegistryConcurrency(t *testing.T) {
+	Convey("Test concurrent registry access", t, func() {
+		r := semver.NewRegistry()
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					v := semver.New(fmt.Sprintf("%d.%d.0", w, i))
+					r.Add("api", v)
+					r.Latest("api", nil, semver.StabilityStable)
+					if i%2 == 1 {
+						r.Remove("api", v)
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		versions := r.Versions("api")
+		So(versions, ShouldHaveLength, 200)
+		for i := 1; i < len(versions); i++ {
+			So(versions[i-1].LT(versions[i]), ShouldBeTrue)
+		}
+	})
+}
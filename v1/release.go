@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"sort"
+	"strconv"
+)
+
+// ReleaseLine is a line of releases that share a major version, or a major
+// and a minor version, such as "1.x" or "1.4.x".
+type ReleaseLine struct {
+	Major uint64
+	Minor uint64
+	// ByMinor is true if the line is a major.minor line, and false if it is
+	// a major line, in which case Minor is zero.
+	ByMinor bool
+	// Versions are the versions of the line, from lowest to highest.
+	Versions Versions
+}
+
+// String returns the line as a wildcard range, such as "1.x" or "1.4.x".
+func (l ReleaseLine) String() string {
+	s := strconv.FormatUint(l.Major, 10)
+	if l.ByMinor {
+		s += "." + strconv.FormatUint(l.Minor, 10)
+	}
+	return s + ".x"
+}
+
+// Latest returns the highest release of the line, which is not a
+// pre-release, or nil if the line only has pre-releases.
+func (l ReleaseLine) Latest() *Version {
+	for i := len(l.Versions) - 1; i >= 0; i-- {
+		if !l.Versions[i].IsPreRelease() {
+			return l.Versions[i]
+		}
+	}
+	return nil
+}
+
+// GroupByMajor groups versions into major release lines, from lowest to
+// highest.
+func GroupByMajor(versions Versions) []ReleaseLine {
+	return groupReleaseLines(versions, false)
+}
+
+// GroupByMinor groups versions into major.minor release lines, from lowest
+// to highest.
+func GroupByMinor(versions Versions) []ReleaseLine {
+	return groupReleaseLines(versions, true)
+}
+
+func groupReleaseLines(versions Versions, byMinor bool) []ReleaseLine {
+	sorted := make(Versions, len(versions))
+	copy(sorted, versions)
+	sort.Stable(sorted)
+
+	var lines []ReleaseLine
+	for _, v := range sorted {
+		line := ReleaseLine{Major: v.Major, ByMinor: byMinor}
+		if byMinor {
+			line.Minor = v.Minor
+		}
+		if n := len(lines); n > 0 && lines[n-1].Major == line.Major && lines[n-1].Minor == line.Minor {
+			lines[n-1].Versions = append(lines[n-1].Versions, v)
+			continue
+		}
+		line.Versions = Versions{v}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// LatestPatches returns the latest release of each major.minor release line
+// of versions, from lowest to highest.  Lines that only have pre-releases
+// are skipped.
+func LatestPatches(versions Versions) Versions {
+	latest := Versions{}
+	for _, line := range GroupByMinor(versions) {
+		if v := line.Latest(); v != nil {
+			latest = append(latest, v)
+		}
+	}
+	return latest
+}
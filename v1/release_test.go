@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestGroupReleaseLines(t *testing.T) {
+	versions := semver.Versions(newVersions("2.0.1", "1.4.0", "1.5.0-rc.1", "1.4.2", "2.0.0", "1.5.0", "1.4.1", "2.1.0-beta.1", "0.9.0"))
+
+	Convey("Test grouping by minor", t, func() {
+		lines := semver.GroupByMinor(versions)
+		var names, latest []string
+		for _, l := range lines {
+			names = append(names, l.String())
+			if v := l.Latest(); v != nil {
+				latest = append(latest, v.String())
+			} else {
+				latest = append(latest, "")
+			}
+		}
+		So(names, ShouldResemble, []string{"0.9.x", "1.4.x", "1.5.x", "2.0.x", "2.1.x"})
+		So(latest, ShouldResemble, []string{"0.9.0", "1.4.2", "1.5.0", "2.0.1", ""})
+		So(versionStrings(lines[2].Versions), ShouldResemble, []string{"1.5.0-rc.1", "1.5.0"})
+	})
+
+	Convey("Test grouping by major", t, func() {
+		lines := semver.GroupByMajor(versions)
+		So(lines, ShouldHaveLength, 3)
+		So(lines[1].String(), ShouldEqual, "1.x")
+		So(versionStrings(lines[1].Versions), ShouldResemble, []string{"1.4.0", "1.4.1", "1.4.2", "1.5.0-rc.1", "1.5.0"})
+		So(lines[2].Latest(), ShouldResemble, semver.New("2.0.1"))
+	})
+
+	Convey("Test latest patches", t, func() {
+		So(versionStrings(semver.LatestPatches(versions)), ShouldResemble, []string{"0.9.0", "1.4.2", "1.5.0", "2.0.1"})
+		So(semver.LatestPatches(nil), ShouldBeEmpty)
+	})
+}
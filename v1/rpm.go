@@ -0,0 +1,296 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const rpmChars = alphanum + "._+~^"
+
+// RPMVersion represents an RPM package version of the form
+// [epoch:]version[-release].
+type RPMVersion struct {
+	Epoch   uint64
+	Version string
+	Release string
+}
+
+// NewRPMVersion parses s to create an instance of RPMVersion.
+// It will return an error if s is not a valid RPM version.
+func NewRPMVersion(s string) (*RPMVersion, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil, errors.New("version string empty")
+	}
+
+	r := &RPMVersion{}
+	if i := strings.IndexRune(s, ':'); i != -1 {
+		if !containsOnly(s[:i], numbers) || i == 0 {
+			return nil, fmt.Errorf("epoch must be a number in %q", s)
+		}
+		epoch, err := strconv.ParseUint(s[:i], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		r.Epoch = epoch
+		s = s[i+1:]
+	}
+
+	if i := strings.LastIndex(s, "-"); i != -1 {
+		r.Release = s[i+1:]
+		s = s[:i]
+		if len(r.Release) == 0 {
+			return nil, errors.New("release is empty")
+		}
+		if !containsOnly(r.Release, rpmChars) {
+			return nil, fmt.Errorf("invalid character found in release %q", r.Release)
+		}
+	}
+
+	if len(s) == 0 {
+		return nil, errors.New("version is empty")
+	}
+	if !containsOnly(s, rpmChars) || strings.ContainsRune(s, '-') {
+		return nil, fmt.Errorf("invalid character found in version %q", s)
+	}
+	r.Version = s
+
+	return r, nil
+}
+
+// MustRPM is a helper for wrapping NewRPMVersion and will panic if err is not nil.
+func MustRPM(r *RPMVersion, err error) *RPMVersion {
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Compare tests if r is less than, equal to, or greater than o using the
+// rpm ordering of epoch, version and release, returning -1, 0, or +1 respectively.
+func (r *RPMVersion) Compare(o *RPMVersion) int {
+	if c := compareUint(r.Epoch, o.Epoch); c != 0 {
+		return c
+	}
+	if c := rpmVerCmp(r.Version, o.Version); c != 0 {
+		return c
+	}
+	return rpmVerCmp(r.Release, o.Release)
+}
+
+// compareIgnoringRelease compares r to o, ignoring the release if o has none,
+// as rpm does when matching dependencies.
+func (r *RPMVersion) compareIgnoringRelease(o *RPMVersion) int {
+	if len(o.Release) == 0 && len(r.Release) != 0 {
+		return r.withoutRelease().Compare(o)
+	}
+	return r.Compare(o)
+}
+
+func (r *RPMVersion) withoutRelease() *RPMVersion {
+	return &RPMVersion{Epoch: r.Epoch, Version: r.Version}
+}
+
+func (r *RPMVersion) String() string {
+	b := make([]byte, 0, len(r.Version)+len(r.Release)+4)
+	if r.Epoch != 0 {
+		b = strconv.AppendUint(b, r.Epoch, 10)
+		b = append(b, ':')
+	}
+	b = append(b, r.Version...)
+	if len(r.Release) > 0 {
+		b = append(b, '-')
+		b = append(b, r.Release...)
+	}
+	return string(b)
+}
+
+func isAlpha(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// rpmVerCmp is a port of rpmvercmp.  Versions are split into alternating
+// runs of digits and letters; other characters only separate runs.  A '~'
+// sorts before anything, even the end of the version, and a '^' sorts
+// after the end of the version but before anything else.
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && !isAlpha(a[i]) && !isDigit(a[i]) && a[i] != '~' && a[i] != '^' {
+			i++
+		}
+		for j < len(b) && !isAlpha(b[j]) && !isDigit(b[j]) && b[j] != '~' && b[j] != '^' {
+			j++
+		}
+
+		if (i < len(a) && a[i] == '~') || (j < len(b) && b[j] == '~') {
+			if i >= len(a) || a[i] != '~' {
+				return 1
+			}
+			if j >= len(b) || b[j] != '~' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		if (i < len(a) && a[i] == '^') || (j < len(b) && b[j] == '^') {
+			if i >= len(a) {
+				return -1
+			}
+			if j >= len(b) {
+				return 1
+			}
+			if a[i] != '^' {
+				return 1
+			}
+			if b[j] != '^' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+
+		si, sj := i, j
+		isNum := isDigit(a[i])
+		if isNum {
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+		} else {
+			for i < len(a) && isAlpha(a[i]) {
+				i++
+			}
+			for j < len(b) && isAlpha(b[j]) {
+				j++
+			}
+		}
+
+		// Segments of different types: numeric is newer
+		if sj == j {
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+
+		segA, segB := a[si:i], b[sj:j]
+		if isNum {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if c := compareInt(len(segA), len(segB)); c != 0 {
+				return c
+			}
+		}
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+
+	if i >= len(a) && j >= len(b) {
+		return 0
+	}
+	if i < len(a) {
+		return 1
+	}
+	return -1
+}
+
+// RPMVersions is an array of RPMVersion pointers for sorting.
+type RPMVersions []*RPMVersion
+
+func (s RPMVersions) Len() int {
+	return len(s)
+}
+
+func (s RPMVersions) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s RPMVersions) Less(i, j int) bool {
+	return s[i].Compare(s[j]) < 0
+}
+
+// RPMRange represents a range of RPM versions.
+type RPMRange func(*RPMVersion) bool
+
+// OR combines the existing RPMRange with another RPMRange using logical OR.
+func (rf RPMRange) OR(f RPMRange) RPMRange {
+	return func(r *RPMVersion) bool {
+		return rf(r) || f(r)
+	}
+}
+
+// AND combines the existing RPMRange with another RPMRange using logical AND.
+func (rf RPMRange) AND(f RPMRange) RPMRange {
+	return func(r *RPMVersion) bool {
+		return rf(r) && f(r)
+	}
+}
+
+// ParseRPMRange parses a range of RPM versions and returns an RPMRange.
+// The syntax is that of ParseRange, without wildcards.  As in rpm
+// dependencies, a version without a release matches every release of it:
+//   - ">= 1:2.3-1 < 1:2.4"
+//   - "= 2.0 || >= 3.0~rc1"
+func ParseRPMRange(s string) (RPMRange, error) {
+	terms, err := splitRangeTerms(s, parseComparison)
+	if err != nil {
+		return nil, err
+	}
+	var orFn RPMRange
+	for _, andTerms := range terms {
+		var andFn RPMRange
+		for _, term := range andTerms {
+			r, err := NewRPMVersion(term.version)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse RPM range %q: %s", s, err)
+			}
+			cmp := term.cmp
+			rf := RPMRange(func(v *RPMVersion) bool {
+				return cmp(v.compareIgnoringRelease(r))
+			})
+			if andFn == nil {
+				andFn = rf
+			} else {
+				andFn = andFn.AND(rf)
+			}
+		}
+		if orFn == nil {
+			orFn = andFn
+		} else {
+			orFn = orFn.OR(andFn)
+		}
+	}
+	return orFn, nil
+}
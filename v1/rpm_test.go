@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func newRPM(s string) *semver.RPMVersion {
+	return semver.MustRPM(semver.NewRPMVersion(s))
+}
+
+func TestNewRPMVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *semver.RPMVersion
+	}{
+		{"1.0", &semver.RPMVersion{Version: "1.0"}},
+		{"2:1.1.1k-7.el8_6", &semver.RPMVersion{Epoch: 2, Version: "1.1.1k", Release: "7.el8_6"}},
+		{"1.0^git1-1", &semver.RPMVersion{Version: "1.0^git1", Release: "1"}},
+		{"", nil},
+		{"a:1.0", nil},
+		{"1.0-", nil},
+		{"1-2-3", nil},
+		{"1.0!", nil},
+	}
+
+	Convey("Test RPM version parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.input, func() {
+				r, err := semver.NewRPMVersion(tc.input)
+				if tc.expected == nil {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(r, ShouldResemble, tc.expected)
+					So(r.String(), ShouldEqual, tc.input)
+				}
+			})
+		}
+	})
+}
+
+func TestRPMCompare(t *testing.T) {
+	// Taken from the rpmvercmp test suite of rpm.
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0.1", "2.0", 1},
+		{"2.0.1a", "2.0.1", 1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"xyz.4", "8", -1},
+		{"xyz.4", "2", -1},
+		{"5.5p1", "5.5.p1", 0},
+		{"10b2", "10a1", 1},
+		{"1.0aa", "1.0a", 1},
+		{"10.0001", "10.1", 0},
+		{"10.0001", "10.0039", -1},
+		{"4.999.9", "5.0", -1},
+		{"20101121", "20101122", -1},
+		{"2_0", "2_0", 0},
+		{"2.0", "2_0", 0},
+		{"a", "a", 0},
+		{"a+", "a_", 0},
+		{"+a", "_a", 0},
+		{"6.0.rc1", "6.0", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~rc1~git123", "1.0~rc1", -1},
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.01", -1},
+		{"1.0^20160101", "1.0.1", -1},
+		{"1.0^20160101^git1", "1.0^20160102", -1},
+		{"1.0~rc1^git1", "1.0~rc1", 1},
+		{"1.0^git1~pre", "1.0^git1", -1},
+		{"1.0-1", "1.0-2", -1},
+		{"1:1.0", "2.0", 1},
+	}
+
+	Convey("Test RPM ordering", t, func() {
+		for _, tc := range tests {
+			Convey(tc.a+" "+tc.b, func() {
+				So(newRPM(tc.a).Compare(newRPM(tc.b)), ShouldEqual, tc.expected)
+				So(newRPM(tc.b).Compare(newRPM(tc.a)), ShouldEqual, -tc.expected)
+			})
+		}
+	})
+
+	Convey("Test RPM sort", t, func() {
+		data := semver.RPMVersions{newRPM("1:0.9"), newRPM("1.0^git1"), newRPM("1.0~beta1"), newRPM("1.0")}
+		sort.Sort(data)
+		So(data, ShouldResemble, semver.RPMVersions{
+			newRPM("1.0~beta1"), newRPM("1.0"), newRPM("1.0^git1"), newRPM("1:0.9"),
+		})
+	})
+}
+
+func TestParseRPMRange(t *testing.T) {
+	type test struct {
+		v        string
+		expected bool
+	}
+	tests := []struct {
+		conditional string
+		data        []test
+	}{
+		{">= 1.1.1k-7.el8_6 < 1.1.1k-9", []test{
+			{"1.1.1k-6", false},
+			{"1.1.1k-7.el8_6", true},
+			{"1.1.1k-8", true},
+			{"1.1.1k-9", false},
+		}},
+		{"= 2.0", []test{
+			{"2.0-1", true},
+			{"2.0-15.el9", true},
+			{"2.0.1-1", false},
+		}},
+		{"< 1:1.0 || >= 2:0", []test{
+			{"3.0", true},
+			{"1:0.9-1", true},
+			{"1:1.0-1", false},
+			{"2:0-1", true},
+		}},
+		{"<< 1.0", nil},
+		{"||", nil},
+		{"", nil},
+		{"^1.0", nil},
+	}
+
+	Convey("Test RPM range parsing", t, func() {
+		for _, tc := range tests {
+			Convey(tc.conditional, func() {
+				r, err := semver.ParseRPMRange(tc.conditional)
+				if tc.data == nil {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					for _, td := range tc.data {
+						Convey(td.v, func() {
+							So(r(newRPM(td.v)), ShouldEqual, td.expected)
+						})
+					}
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semvertest
+
+import (
+	"fmt"
+
+	"l7e.io/semver/v1"
+)
+
+const success = ""
+
+// ShouldBeNewerThan asserts that the actual version has a higher precedence
+// than the expected version.  Versions are either a *semver.Version, a
+// semvertest.Version or a string.
+func ShouldBeNewerThan(actual interface{}, expected ...interface{}) string {
+	return compareVersions(actual, expected, "newer than", func(c int) bool { return c > 0 })
+}
+
+// ShouldBeOlderThan asserts that the actual version has a lower precedence
+// than the expected version.
+func ShouldBeOlderThan(actual interface{}, expected ...interface{}) string {
+	return compareVersions(actual, expected, "older than", func(c int) bool { return c < 0 })
+}
+
+// ShouldHaveSamePrecedenceAs asserts that the actual version has the same
+// precedence as the expected version, so build metadata is ignored.
+func ShouldHaveSamePrecedenceAs(actual interface{}, expected ...interface{}) string {
+	return compareVersions(actual, expected, "of the same precedence as", func(c int) bool { return c == 0 })
+}
+
+// ShouldSatisfy asserts that the actual version satisfies the expected range,
+// which is either a semver.Range, a semvertest.Range or a string that is
+// parsed with semver.ParseRange.
+func ShouldSatisfy(actual interface{}, expected ...interface{}) string {
+	return satisfy(actual, expected, true)
+}
+
+// ShouldNotSatisfy asserts that the actual version does not satisfy the
+// expected range.
+func ShouldNotSatisfy(actual interface{}, expected ...interface{}) string {
+	return satisfy(actual, expected, false)
+}
+
+func compareVersions(actual interface{}, expected []interface{}, relation string, ok func(int) bool) string {
+	if len(expected) != 1 {
+		return fmt.Sprintf("This assertion requires exactly 1 comparison value (you provided %d).", len(expected))
+	}
+	v, msg := toVersion(actual)
+	if v == nil {
+		return msg
+	}
+	o, msg := toVersion(expected[0])
+	if o == nil {
+		return msg
+	}
+	if !ok(v.Compare(o)) {
+		return fmt.Sprintf("Expected '%s' to be %s '%s' (but it wasn't)!", v, relation, o)
+	}
+	return success
+}
+
+func satisfy(actual interface{}, expected []interface{}, want bool) string {
+	if len(expected) != 1 {
+		return fmt.Sprintf("This assertion requires exactly 1 comparison value (you provided %d).", len(expected))
+	}
+	v, msg := toVersion(actual)
+	if v == nil {
+		return msg
+	}
+
+	var r semver.Range
+	var err error
+	var expr interface{} = expected[0]
+	switch e := expected[0].(type) {
+	case semver.Range:
+		r, expr = e, "the range"
+	case Range:
+		r, expr = e.Range, e.Expr
+	case string:
+		if r, err = semver.ParseRange(e); err != nil {
+			return fmt.Sprintf("Expected a valid range (but it was '%s': %s)!", e, err)
+		}
+	default:
+		return fmt.Sprintf("Expected a range (but it was %T)!", expected[0])
+	}
+
+	if r(v) != want {
+		if want {
+			return fmt.Sprintf("Expected '%s' to satisfy '%s' (but it didn't)!", v, expr)
+		}
+		return fmt.Sprintf("Expected '%s' not to satisfy '%s' (but it did)!", v, expr)
+	}
+	return success
+}
+
+// toVersion returns i as a version, or nil and the failure message.
+func toVersion(i interface{}) (*semver.Version, string) {
+	switch v := i.(type) {
+	case *semver.Version:
+		if v != nil {
+			return v, success
+		}
+	case Version:
+		if v.Version != nil {
+			return v.Version, success
+		}
+	case string:
+		parsed, err := semver.NewVersion(v)
+		if err != nil {
+			return nil, fmt.Sprintf("Expected a valid version (but it was '%s': %s)!", v, err)
+		}
+		return parsed, success
+	}
+	return nil, fmt.Sprintf("Expected a version (but it was %T %v)!", i, i)
+}
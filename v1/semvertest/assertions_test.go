@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semvertest_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+	"l7e.io/semver/v1/semvertest"
+)
+
+func TestAssertions(t *testing.T) {
+	Convey("Test version assertions", t, func() {
+		So(semver.New("1.2.4"), semvertest.ShouldBeNewerThan, "1.2.3")
+		So("1.2.3", semvertest.ShouldBeOlderThan, semver.New("1.2.4"))
+		So(semvertest.Version{Version: semver.New("1.2.3-rc.1")}, semvertest.ShouldBeOlderThan, "1.2.3")
+		So("1.2.3+a", semvertest.ShouldHaveSamePrecedenceAs, "1.2.3+b")
+
+		So(semvertest.ShouldBeNewerThan("1.2.3", "1.2.3"), ShouldEqual, "Expected '1.2.3' to be newer than '1.2.3' (but it wasn't)!")
+		So(semvertest.ShouldBeOlderThan("1.2.3", "1.2.2"), ShouldNotBeEmpty)
+		So(semvertest.ShouldHaveSamePrecedenceAs("1.2.3", "1.2.3-rc"), ShouldNotBeEmpty)
+		So(semvertest.ShouldBeNewerThan("1.2", "1.2.3"), ShouldStartWith, "Expected a valid version")
+		So(semvertest.ShouldBeNewerThan(42, "1.2.3"), ShouldStartWith, "Expected a version")
+		So(semvertest.ShouldBeNewerThan((*semver.Version)(nil), "1.2.3"), ShouldStartWith, "Expected a version")
+		So(semvertest.ShouldBeNewerThan("1.2.3"), ShouldStartWith, "This assertion requires exactly 1 comparison value")
+	})
+
+	Convey("Test range assertions", t, func() {
+		So("1.2.3", semvertest.ShouldSatisfy, ">=1.0.0 <2.0.0")
+		So(semver.New("1.2.3"), semvertest.ShouldSatisfy, semver.MustParseRange("1.x"))
+		So("2.0.0", semvertest.ShouldNotSatisfy, semvertest.Range{Expr: "1.x", Range: semver.MustParseRange("1.x")})
+
+		So(semvertest.ShouldSatisfy("2.0.0", ">=1.0.0 <2.0.0"), ShouldEqual, "Expected '2.0.0' to satisfy '>=1.0.0 <2.0.0' (but it didn't)!")
+		So(semvertest.ShouldNotSatisfy("1.0.0", "1.x"), ShouldEqual, "Expected '1.0.0' not to satisfy '1.x' (but it did)!")
+		So(semvertest.ShouldSatisfy("1.0.0", ">=foo"), ShouldStartWith, "Expected a valid range")
+		So(semvertest.ShouldSatisfy("1.0.0", 42), ShouldStartWith, "Expected a range")
+		So(semvertest.ShouldSatisfy("1.0.0"), ShouldStartWith, "This assertion requires exactly 1 comparison value")
+	})
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+/*
+Package semvertest provides support for testing code that uses semver:
+generators for testing/quick, the semver.org corpus of valid and invalid
+version strings, and goconvey assertions.
+
+	func TestUpgrade(t *testing.T) {
+		Convey("Upgrades are newer", t, func() {
+			So(upgrade(semver.New("1.2.3")), semvertest.ShouldBeNewerThan, "1.2.3")
+			So(upgrade(semver.New("1.2.3")), semvertest.ShouldSatisfy, "<2.0.0")
+		})
+	}
+*/
+package semvertest // import "l7e.io/semver/v1/semvertest"
+
+import "regexp"
+
+// Pattern is the regular expression suggested by semver.org that matches
+// valid Semantic Versioning 2.0.0 version strings.
+const Pattern = `^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`
+
+// Regexp is the compiled Pattern.
+var Regexp = regexp.MustCompile(Pattern)
+
+// Valid is the corpus of valid version strings that semver.org links to
+// from the regular expression.  Versions with numbers that do not fit into
+// an uint64 are in Overflow instead.
+var Valid = []string{
+	"0.0.4",
+	"1.2.3",
+	"10.20.30",
+	"1.1.2-prerelease+meta",
+	"1.1.2+meta",
+	"1.1.2+meta-valid",
+	"1.0.0-alpha",
+	"1.0.0-beta",
+	"1.0.0-alpha.beta",
+	"1.0.0-alpha.beta.1",
+	"1.0.0-alpha.1",
+	"1.0.0-alpha0.valid",
+	"1.0.0-alpha.0valid",
+	"1.0.0-alpha-a.b-c-somethinglong+build.1-aef.1-its-okay",
+	"1.0.0-rc.1+build.1",
+	"2.0.0-rc.1+build.123",
+	"1.2.3-beta",
+	"10.2.3-DEV-SNAPSHOT",
+	"1.2.3-SNAPSHOT-123",
+	"1.0.0",
+	"2.0.0",
+	"1.1.7",
+	"2.0.0+build.1848",
+	"2.0.1-alpha.1227",
+	"1.0.0-alpha+beta",
+	"1.2.3----RC-SNAPSHOT.12.9.1--.12+788",
+	"1.2.3----R-S.12.9.1--.12+meta",
+	"1.2.3----RC-SNAPSHOT.12.9.1--.12",
+	"1.0.0+0.build.1-rc.10000aaa-kk-0.1",
+	"1.0.0-0A.is.legal",
+}
+
+// Overflow is the part of the semver.org corpus of valid version strings
+// with numbers that do not fit into an uint64, which semver rejects.
+var Overflow = []string{
+	"99999999999999999999999.999999999999999999.99999999999999999",
+}
+
+// Invalid is the corpus of invalid version strings that semver.org links
+// to from the regular expression.
+var Invalid = []string{
+	"1",
+	"1.2",
+	"1.2.3-0123",
+	"1.2.3-0123.0123",
+	"1.1.2+.123",
+	"+invalid",
+	"-invalid",
+	"-invalid+invalid",
+	"-invalid.01",
+	"alpha",
+	"alpha.beta",
+	"alpha.beta.1",
+	"alpha.1",
+	"alpha+beta",
+	"alpha_beta",
+	"alpha.",
+	"alpha..",
+	"beta",
+	"1.0.0-alpha_beta",
+	"-alpha.",
+	"1.0.0-alpha..",
+	"1.0.0-alpha..1",
+	"1.0.0-alpha...1",
+	"1.0.0-alpha....1",
+	"1.0.0-alpha.....1",
+	"1.0.0-alpha......1",
+	"1.0.0-alpha.......1",
+	"01.1.1",
+	"1.01.1",
+	"1.1.01",
+	"1.2.3.DEV",
+	"1.2-SNAPSHOT",
+	"1.2.31.2.3----RC-SNAPSHOT.12.09.1--..12+788",
+	"1.2-RC-SNAPSHOT",
+	"-1.0.3-gamma+b7718",
+	"+justmeta",
+	"9.8.7+meta+meta",
+	"9.8.7-whatever+meta+meta",
+	"99999999999999999999999.999999999999999999.99999999999999999----RC-SNAPSHOT.12.09.1--------------------------------..12",
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semvertest_test
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/quick"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+	"l7e.io/semver/v1/semvertest"
+)
+
+func TestCorpus(t *testing.T) {
+	Convey("Test valid versions", t, func() {
+		for _, s := range semvertest.Valid {
+			Convey(s, func() {
+				So(semvertest.Regexp.MatchString(s), ShouldBeTrue)
+				v, err := semver.NewVersion(s)
+				So(err, ShouldBeNil)
+				So(v.String(), ShouldEqual, s)
+			})
+		}
+	})
+
+	Convey("Test valid versions that overflow", t, func() {
+		for _, s := range semvertest.Overflow {
+			Convey(s, func() {
+				So(semvertest.Regexp.MatchString(s), ShouldBeTrue)
+				_, err := semver.NewVersion(s)
+				So(errors.Is(err, strconv.ErrRange), ShouldBeTrue)
+			})
+		}
+	})
+
+	Convey("Test invalid versions", t, func() {
+		for _, s := range semvertest.Invalid {
+			Convey(s, func() {
+				So(semvertest.Regexp.MatchString(s), ShouldBeFalse)
+				_, err := semver.NewVersion(s)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
+
+// mutant is a version string from the corpus with random edits.
+type mutant string
+
+const mutations = "0123456789.-+aZx_ "
+
+func (mutant) Generate(r *rand.Rand, size int) reflect.Value {
+	corpus := append(append([]string{}, semvertest.Valid...), semvertest.Invalid...)
+	b := []byte(corpus[r.Intn(len(corpus))])
+	for n := r.Intn(4); n > 0; n-- {
+		c := mutations[r.Intn(len(mutations))]
+		i := r.Intn(len(b) + 1)
+		switch r.Intn(3) {
+		case 0:
+			b = append(b[:i], append([]byte{c}, b[i:]...)...)
+		case 1:
+			if i < len(b) {
+				b = append(b[:i], b[i+1:]...)
+			}
+		default:
+			if i < len(b) {
+				b[i] = c
+			}
+		}
+	}
+	return reflect.ValueOf(mutant(b))
+}
+
+func TestSetConformance(t *testing.T) {
+	Convey("Test Version.Set accepts exactly what the semver.org regexp matches", t, func() {
+		conforms := func(m mutant) bool {
+			s := string(m)
+			var v semver.Version
+			err := v.Set(s)
+			if errors.Is(err, strconv.ErrRange) {
+				// Numbers that do not fit into an uint64 are rejected before
+				// the rest of the version is checked.
+				return true
+			}
+			if (err == nil) != semvertest.Regexp.MatchString(s) {
+				return false
+			}
+			return err != nil || v.String() == s
+		}
+		So(quick.Check(conforms, &quick.Config{MaxCount: 20000}), ShouldBeNil)
+	})
+}
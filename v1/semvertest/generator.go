@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semvertest
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"l7e.io/semver/v1"
+)
+
+const (
+	identifierChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-0123456789"
+	letterChars     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-"
+)
+
+// Version is a *semver.Version that implements quick.Generator.  Generated
+// versions are valid, with numbers up to size and, at random, pre-release
+// identifiers and build metadata.
+type Version struct {
+	*semver.Version
+}
+
+// Generate implements the testing/quick.Generator interface.
+func (Version) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Version{generateVersion(r, size)})
+}
+
+func generateVersion(r *rand.Rand, size int) *semver.Version {
+	v := &semver.Version{
+		Major:      generateNumber(r, size),
+		Minor:      generateNumber(r, size),
+		Patch:      generateNumber(r, size),
+		PreRelease: semver.Identifiers{},
+		Metadata:   semver.Identifiers{},
+	}
+	if r.Intn(2) == 0 {
+		v.PreRelease = generateIdentifiers(r, size, true)
+	}
+	if r.Intn(4) == 0 {
+		v.Metadata = generateIdentifiers(r, size, false)
+	}
+	return v
+}
+
+// Identifiers is a semver.Identifiers that implements quick.Generator.
+// Generated identifiers are valid pre-release identifiers, and there is
+// at least one of them.
+type Identifiers semver.Identifiers
+
+// Generate implements the testing/quick.Generator interface.
+func (Identifiers) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Identifiers(generateIdentifiers(r, size, true)))
+}
+
+func generateNumber(r *rand.Rand, size int) uint64 {
+	if size < 1 {
+		size = 1
+	}
+	return uint64(r.Intn(size))
+}
+
+func generateIdentifiers(r *rand.Rand, size int, pre bool) semver.Identifiers {
+	ids := make(semver.Identifiers, 1+r.Intn(3))
+	for i := range ids {
+		ids[i] = generateIdentifier(r, size, pre)
+	}
+	return ids
+}
+
+func generateIdentifier(r *rand.Rand, size int, pre bool) semver.Identifier {
+	if pre && r.Intn(2) == 0 {
+		return semver.Identifier{Num: generateNumber(r, size), IsNum: true}
+	}
+	n := 1 + r.Intn(8)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = identifierChars[r.Intn(len(identifierChars))]
+	}
+	if pre {
+		// An alphanumeric pre-release identifier needs a non-digit.
+		b[r.Intn(n)] = letterChars[r.Intn(len(letterChars))]
+	}
+	return semver.Identifier{Str: string(b)}
+}
+
+// Range is a range expression accepted by semver.ParseRange that implements
+// quick.Generator, along with the parsed Range.  Generated expressions have
+// one to three alternatives separated by "||", each with one or two
+// comparators.  Versions may be wildcards such as "1.x" or have alpha, beta
+// or rc pre-releases.
+type Range struct {
+	Expr  string
+	Range semver.Range
+}
+
+var rangeOperators = []string{"", "=", "==", "!=", "<", "<=", ">", ">="}
+
+// Generate implements the testing/quick.Generator interface.
+func (Range) Generate(r *rand.Rand, size int) reflect.Value {
+	alternatives := make([]string, 1+r.Intn(3))
+	for i := range alternatives {
+		comparators := make([]string, 1+r.Intn(2))
+		for j := range comparators {
+			comparators[j] = generateComparator(r, size)
+		}
+		alternatives[i] = strings.Join(comparators, " ")
+	}
+	expr := strings.Join(alternatives, " || ")
+	return reflect.ValueOf(Range{Expr: expr, Range: semver.MustParseRange(expr)})
+}
+
+var rangePreReleases = []string{"alpha", "beta", "rc"}
+
+func generateComparator(r *rand.Rand, size int) string {
+	major := strconv.FormatUint(generateNumber(r, size), 10)
+	minor := strconv.FormatUint(generateNumber(r, size), 10)
+	op := rangeOperators[r.Intn(len(rangeOperators))]
+
+	switch r.Intn(6) {
+	case 0:
+		return op + major + ".x"
+	case 1:
+		return op + major + "." + minor + ".x"
+	case 2:
+		pre := rangePreReleases[r.Intn(len(rangePreReleases))]
+		return op + major + "." + minor + ".0-" + pre + "." + strconv.FormatUint(generateNumber(r, size), 10)
+	}
+	return op + major + "." + minor + "." + strconv.FormatUint(generateNumber(r, size), 10)
+}
+
+func (r Range) String() string {
+	return r.Expr
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semvertest_test
+
+import (
+	"testing"
+	"testing/quick"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+	"l7e.io/semver/v1/semvertest"
+)
+
+func TestGenerators(t *testing.T) {
+	Convey("Test generated versions are valid", t, func() {
+		valid := func(v semvertest.Version) bool {
+			parsed, err := semver.NewVersion(v.String())
+			return err == nil && semvertest.Regexp.MatchString(v.String()) && parsed.String() == v.String()
+		}
+		So(quick.Check(valid, nil), ShouldBeNil)
+	})
+
+	Convey("Test generated identifiers are valid pre-releases", t, func() {
+		valid := func(ids semvertest.Identifiers) bool {
+			v, err := semver.NewVersion("1.0.0-" + semver.Identifiers(ids).String())
+			return err == nil && len(ids) > 0 && v.PreRelease.Compare(semver.Identifiers(ids)) == 0
+		}
+		So(quick.Check(valid, nil), ShouldBeNil)
+	})
+
+	Convey("Test generated ranges parse", t, func() {
+		valid := func(r semvertest.Range, v semvertest.Version) bool {
+			parsed, err := semver.ParseRange(r.String())
+			return err == nil && parsed(v.Version) == r.Range(v.Version)
+		}
+		So(quick.Check(valid, nil), ShouldBeNil)
+	})
+
+	Convey("Test Compare is antisymmetric for generated versions", t, func() {
+		antisymmetric := func(a, b semvertest.Version) bool {
+			return a.Compare(b.Version) == -b.Compare(a.Version)
+		}
+		So(quick.Check(antisymmetric, nil), ShouldBeNil)
+	})
+}
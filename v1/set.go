@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"sort"
+	"strings"
+)
+
+// Dedup selects which versions of a VersionSet are duplicates of each other.
+type Dedup int
+
+const (
+	// DedupPrecedence treats versions with the same precedence as
+	// duplicates, so that versions that only differ in build metadata are
+	// stored once.
+	DedupPrecedence Dedup = iota
+	// DedupMetadata treats versions as duplicates only if they also have the
+	// same build metadata.  Versions with the same precedence are then
+	// ordered by their build metadata.
+	DedupMetadata
+)
+
+// VersionSet is a set of versions kept in ascending order, which answers
+// queries with a binary search.  It keeps the versions that are added, which
+// must not be modified while they are in the set.  It is not safe for
+// concurrent use.
+type VersionSet struct {
+	dedup    Dedup
+	versions Versions
+}
+
+// NewVersionSet returns a VersionSet of the given versions, of which the
+// first of each set of duplicates is kept.
+func NewVersionSet(dedup Dedup, versions ...*Version) *VersionSet {
+	s := &VersionSet{dedup: dedup}
+	for _, v := range versions {
+		s.Add(v)
+	}
+	return s
+}
+
+// compare orders versions by precedence and, with DedupMetadata, then by
+// build metadata.
+func (s *VersionSet) compare(a, b *Version) int {
+	if c := a.Compare(b); c != 0 || s.dedup == DedupPrecedence {
+		return c
+	}
+	return strings.Compare(a.Metadata.String(), b.Metadata.String())
+}
+
+// search returns the index of the first version of s that is not lower
+// than v.
+func (s *VersionSet) search(v *Version) int {
+	return sort.Search(len(s.versions), func(i int) bool {
+		return s.compare(s.versions[i], v) >= 0
+	})
+}
+
+// Len returns the number of versions in s.
+func (s *VersionSet) Len() int {
+	return len(s.versions)
+}
+
+// Add adds v to s.  It returns false if s already contains a duplicate of v.
+func (s *VersionSet) Add(v *Version) bool {
+	i := s.search(v)
+	if i < len(s.versions) && s.compare(s.versions[i], v) == 0 {
+		return false
+	}
+	s.versions = append(s.versions, nil)
+	copy(s.versions[i+1:], s.versions[i:])
+	s.versions[i] = v
+	return true
+}
+
+// Remove removes the duplicate of v from s.  It returns false if s does not
+// contain one.
+func (s *VersionSet) Remove(v *Version) bool {
+	i := s.search(v)
+	if i == len(s.versions) || s.compare(s.versions[i], v) != 0 {
+		return false
+	}
+	s.versions = append(s.versions[:i], s.versions[i+1:]...)
+	return true
+}
+
+// Contains returns true if s contains a duplicate of v.
+func (s *VersionSet) Contains(v *Version) bool {
+	i := s.search(v)
+	return i < len(s.versions) && s.compare(s.versions[i], v) == 0
+}
+
+// Min returns the lowest version of s, or nil if s is empty.
+func (s *VersionSet) Min() *Version {
+	if len(s.versions) == 0 {
+		return nil
+	}
+	return s.versions[0]
+}
+
+// Max returns the highest version of s, or nil if s is empty.
+func (s *VersionSet) Max() *Version {
+	if len(s.versions) == 0 {
+		return nil
+	}
+	return s.versions[len(s.versions)-1]
+}
+
+// Floor returns the highest version of s that is lower than or a duplicate
+// of v, or nil if there is none.
+func (s *VersionSet) Floor(v *Version) *Version {
+	i := s.search(v)
+	if i < len(s.versions) && s.compare(s.versions[i], v) == 0 {
+		return s.versions[i]
+	}
+	return s.at(i - 1)
+}
+
+// Ceiling returns the lowest version of s that is higher than or a
+// duplicate of v, or nil if there is none.
+func (s *VersionSet) Ceiling(v *Version) *Version {
+	return s.at(s.search(v))
+}
+
+// Prev returns the highest version of s that is lower than v, or nil if
+// there is none.
+func (s *VersionSet) Prev(v *Version) *Version {
+	return s.at(s.search(v) - 1)
+}
+
+// Next returns the lowest version of s that is higher than v, or nil if
+// there is none.
+func (s *VersionSet) Next(v *Version) *Version {
+	i := s.search(v)
+	if i < len(s.versions) && s.compare(s.versions[i], v) == 0 {
+		i++
+	}
+	return s.at(i)
+}
+
+func (s *VersionSet) at(i int) *Version {
+	if i < 0 || i >= len(s.versions) {
+		return nil
+	}
+	return s.versions[i]
+}
+
+// Ascend calls fn for the versions of s from lowest to highest, until fn
+// returns false.
+func (s *VersionSet) Ascend(fn func(v *Version) bool) {
+	for _, v := range s.versions {
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+// Descend calls fn for the versions of s from highest to lowest, until fn
+// returns false.
+func (s *VersionSet) Descend(fn func(v *Version) bool) {
+	for i := len(s.versions) - 1; i >= 0; i-- {
+		if !fn(s.versions[i]) {
+			return
+		}
+	}
+}
+
+// Versions returns the versions of s from lowest to highest.
+func (s *VersionSet) Versions() Versions {
+	versions := make(Versions, len(s.versions))
+	copy(versions, s.versions)
+	return versions
+}
+
+// Slice returns a VersionSet of the versions of s from lower, inclusive, up
+// to upper, exclusive.  A nil bound is unbounded.
+func (s *VersionSet) Slice(lower, upper *Version) *VersionSet {
+	from, to := 0, len(s.versions)
+	if lower != nil {
+		from = s.search(lower)
+	}
+	if upper != nil {
+		to = s.search(upper)
+	}
+	if to < from {
+		to = from
+	}
+	versions := make(Versions, to-from)
+	copy(versions, s.versions[from:to])
+	return &VersionSet{dedup: s.dedup, versions: versions}
+}
+
+// Filter returns a VersionSet of the versions of s that satisfy c.
+func (s *VersionSet) Filter(c *Constraint) *VersionSet {
+	return &VersionSet{dedup: s.dedup, versions: c.FilterVersions(s.versions)}
+}
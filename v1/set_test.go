@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func versionStrings(versions semver.Versions) []string {
+	strs := []string{}
+	for _, v := range versions {
+		strs = append(strs, v.String())
+	}
+	return strs
+}
+
+func newVersions(strs ...string) []*semver.Version {
+	versions := make([]*semver.Version, len(strs))
+	for i, s := range strs {
+		versions[i] = semver.New(s)
+	}
+	return versions
+}
+
+func TestVersionSetDedup(t *testing.T) {
+	versions := newVersions("2.0.0", "1.0.0+b", "1.0.0-rc.1", "1.0.0+a", "1.0.0", "2.0.0", "0.9.0")
+
+	Convey("Test version set de-duplication", t, func() {
+		Convey("by precedence", func() {
+			s := semver.NewVersionSet(semver.DedupPrecedence, versions...)
+			So(versionStrings(s.Versions()), ShouldResemble, []string{"0.9.0", "1.0.0-rc.1", "1.0.0+b", "2.0.0"})
+			So(s.Len(), ShouldEqual, 4)
+			So(s.Add(semver.New("1.0.0+c")), ShouldBeFalse)
+			So(s.Contains(semver.New("1.0.0")), ShouldBeTrue)
+			So(s.Remove(semver.New("1.0.0")), ShouldBeTrue)
+			So(s.Contains(semver.New("1.0.0+b")), ShouldBeFalse)
+		})
+		Convey("by metadata", func() {
+			s := semver.NewVersionSet(semver.DedupMetadata, versions...)
+			So(versionStrings(s.Versions()), ShouldResemble, []string{"0.9.0", "1.0.0-rc.1", "1.0.0", "1.0.0+a", "1.0.0+b", "2.0.0"})
+			So(s.Add(semver.New("1.0.0+c")), ShouldBeTrue)
+			So(s.Add(semver.New("1.0.0+a")), ShouldBeFalse)
+			So(s.Remove(semver.New("1.0.0+a")), ShouldBeTrue)
+			So(s.Remove(semver.New("1.0.0+a")), ShouldBeFalse)
+			So(s.Contains(semver.New("1.0.0+b")), ShouldBeTrue)
+		})
+	})
+}
+
+func TestVersionSetQueries(t *testing.T) {
+	s := semver.NewVersionSet(semver.DedupPrecedence, newVersions("1.0.0", "1.1.0", "1.2.0-beta.1", "1.2.0", "2.0.0")...)
+
+	tests := []struct {
+		v                          string
+		floor, ceiling, prev, next string
+	}{
+		{"0.1.0", "", "1.0.0", "", "1.0.0"},
+		{"1.0.0", "1.0.0", "1.0.0", "", "1.1.0"},
+		{"1.1.5", "1.1.0", "1.2.0-beta.1", "1.1.0", "1.2.0-beta.1"},
+		{"1.2.0+build", "1.2.0", "1.2.0", "1.2.0-beta.1", "2.0.0"},
+		{"2.0.0", "2.0.0", "2.0.0", "1.2.0", ""},
+		{"3.0.0", "2.0.0", "", "2.0.0", ""},
+	}
+
+	str := func(v *semver.Version) string {
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	}
+
+	Convey("Test version set queries", t, func() {
+		for _, tc := range tests {
+			Convey(tc.v, func() {
+				v := semver.New(tc.v)
+				So(str(s.Floor(v)), ShouldEqual, tc.floor)
+				So(str(s.Ceiling(v)), ShouldEqual, tc.ceiling)
+				So(str(s.Prev(v)), ShouldEqual, tc.prev)
+				So(str(s.Next(v)), ShouldEqual, tc.next)
+			})
+		}
+
+		Convey("Min and max", func() {
+			So(s.Min(), ShouldResemble, semver.New("1.0.0"))
+			So(s.Max(), ShouldResemble, semver.New("2.0.0"))
+			empty := semver.NewVersionSet(semver.DedupPrecedence)
+			So(empty.Min(), ShouldBeNil)
+			So(empty.Max(), ShouldBeNil)
+			So(empty.Floor(semver.New("1.0.0")), ShouldBeNil)
+		})
+	})
+}
+
+func TestVersionSetIteration(t *testing.T) {
+	s := semver.NewVersionSet(semver.DedupPrecedence, newVersions("1.1.0", "2.0.0", "1.0.0", "1.2.0")...)
+
+	Convey("Test version set iteration", t, func() {
+		var ascending, descending []string
+		s.Ascend(func(v *semver.Version) bool {
+			ascending = append(ascending, v.String())
+			return true
+		})
+		s.Descend(func(v *semver.Version) bool {
+			descending = append(descending, v.String())
+			return v.GT(semver.New("1.1.0"))
+		})
+		So(ascending, ShouldResemble, []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"})
+		So(descending, ShouldResemble, []string{"2.0.0", "1.2.0", "1.1.0"})
+	})
+
+	Convey("Test version set slicing", t, func() {
+		So(versionStrings(s.Slice(semver.New("1.1.0"), semver.New("2.0.0")).Versions()), ShouldResemble, []string{"1.1.0", "1.2.0"})
+		So(versionStrings(s.Slice(nil, semver.New("1.1.0")).Versions()), ShouldResemble, []string{"1.0.0"})
+		So(versionStrings(s.Slice(semver.New("1.1.5"), nil).Versions()), ShouldResemble, []string{"1.2.0", "2.0.0"})
+		So(versionStrings(s.Slice(semver.New("2.0.0"), semver.New("1.0.0")).Versions()), ShouldBeEmpty)
+		So(versionStrings(s.Filter(semver.MustParseConstraint("1.x !=1.1.0")).Versions()), ShouldResemble, []string{"1.0.0", "1.2.0"})
+
+		sliced := s.Slice(nil, nil)
+		sliced.Add(semver.New("3.0.0"))
+		So(s.Len(), ShouldEqual, 4)
+	})
+}
@@ -40,3 +40,23 @@ func (v *Version) Scan(src interface{}) error {
 func (v Version) Value() (driver.Value, error) {
 	return v.String(), nil
 }
+
+// Scan implements the database/sql.Scanner interface.
+func (c *Constraint) Scan(src interface{}) error {
+	var str string
+	switch src := src.(type) {
+	case string:
+		str = src
+	case []byte:
+		str = string(src)
+	default:
+		return fmt.Errorf("cannot convert %T to string", src)
+	}
+
+	return c.Set(str)
+}
+
+// Value implements the database/sql/driver.Valuer interface.
+func (c Constraint) Value() (driver.Value, error) {
+	return c.String(), nil
+}
@@ -56,3 +56,30 @@ func TestScanString(t *testing.T) {
 		}
 	})
 }
+
+var constraintScanTests = []scanTest{
+	{"scan constraint string", ">=1.2.3 <2.0.0", false, ">=1.2.3 <2.0.0"},
+	{"scan constraint bytes", []byte("1.x || 3.x"), false, "1.x || 3.x"},
+	{"scan invalid constraint", "1.x ||", true, ""},
+	{"scan constraint integer", 7, true, ""},
+	{"scan constraint nil", nil, true, ""},
+}
+
+func TestScanConstraint(t *testing.T) {
+	Convey("Test Constraint Scan()", t, func() {
+		for _, tc := range constraintScanTests {
+			Convey(tc.name, func() {
+				c := &semver.Constraint{}
+				err := c.Scan(tc.val)
+				if tc.shouldError {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					val, err := c.Value()
+					So(err, ShouldBeNil)
+					So(val, ShouldEqual, tc.expected)
+				}
+			})
+		}
+	})
+}
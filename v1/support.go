@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver
+
+import (
+	"sort"
+	"strconv"
+)
+
+// SupportStatus is the support status of a version under a SupportPolicy.
+type SupportStatus int
+
+// The support statuses, from most to least supported.
+const (
+	StatusSupported SupportStatus = iota
+	StatusDeprecated
+	StatusEOL
+)
+
+var supportStatusNames = []string{"supported", "deprecated", "EOL"}
+
+func (s SupportStatus) String() string {
+	if s < StatusSupported || s > StatusEOL {
+		return "SupportStatus(" + strconv.Itoa(int(s)) + ")"
+	}
+	return supportStatusNames[s]
+}
+
+// SupportPolicy supports the latest release of the last Minors major.minor
+// release lines of the current major version, which is the major version of
+// the highest release, plus the latest release of the latest line of each of
+// the PreviousMajors major versions before it.  For example, the policy
+// "support the last two minors of the current major plus the latest minor of
+// the previous major" is
+//
+//	SupportPolicy{Minors: 2, PreviousMajors: 1}
+type SupportPolicy struct {
+	Minors         int
+	PreviousMajors int
+}
+
+// VersionSupport is the support status of a version.
+type VersionSupport struct {
+	Version *Version
+	Status  SupportStatus
+	// Upgrade is the lowest supported version above a deprecated version
+	// that it is compatible under, if any.
+	Upgrade *Version
+}
+
+// SupportedLines returns the major.minor release lines of versions that p
+// supports, from lowest to highest.  Lines that only have pre-releases are
+// not supported.
+func (p SupportPolicy) SupportedLines(versions Versions) []ReleaseLine {
+	var released []ReleaseLine
+	for _, line := range GroupByMinor(versions) {
+		if line.Latest() != nil {
+			released = append(released, line)
+		}
+	}
+	if len(released) == 0 {
+		return nil
+	}
+
+	var supported []ReleaseLine
+	current := released[len(released)-1].Major
+	previous := current
+	minors, majors := 0, 0
+	for i := len(released) - 1; i >= 0; i-- {
+		line := released[i]
+		switch {
+		case line.Major == current:
+			if minors < p.Minors {
+				supported = append(supported, line)
+				minors++
+			}
+		case line.Major != previous:
+			// The first line of a previous major version is its latest.
+			previous = line.Major
+			if majors < p.PreviousMajors {
+				supported = append(supported, line)
+				majors++
+			}
+		}
+	}
+
+	sort.Slice(supported, func(i, j int) bool {
+		return supported[i].Latest().LT(supported[j].Latest())
+	})
+	return supported
+}
+
+// Evaluate returns the support status of each of versions under p, from
+// lowest to highest version.  The latest release of a supported line is
+// supported.  Any other version that is compatible under a higher supported
+// version, as reported by CompatibleUnder, is deprecated, as it can be
+// upgraded to a supported version of the same major version.  Every other
+// version is EOL.  Pre-releases are never supported, so a pre-release above
+// the latest release of its line, such as 2.3.1-rc.1 when 2.3.0 is
+// supported, is EOL like the pre-releases of a line without releases.
+func (p SupportPolicy) Evaluate(versions Versions) []VersionSupport {
+	var supported Versions
+	for _, line := range p.SupportedLines(versions) {
+		supported = append(supported, line.Latest())
+	}
+
+	sorted := make(Versions, len(versions))
+	copy(sorted, versions)
+	sort.Stable(sorted)
+
+	statuses := make([]VersionSupport, len(sorted))
+	for i, v := range sorted {
+		statuses[i] = VersionSupport{Version: v, Status: StatusEOL}
+		for _, s := range supported {
+			if !v.IsPreRelease() && v.EQ(s) {
+				statuses[i] = VersionSupport{Version: v, Status: StatusSupported}
+				break
+			}
+			if v.CompatibleUnder(s) && s.GT(v) {
+				statuses[i].Status = StatusDeprecated
+				if statuses[i].Upgrade == nil {
+					statuses[i].Upgrade = s
+				}
+			}
+		}
+	}
+	return statuses
+}
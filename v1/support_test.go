@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semver_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"l7e.io/semver/v1"
+)
+
+func TestSupportPolicy(t *testing.T) {
+	versions := semver.Versions(newVersions(
+		"0.9.0",
+		"1.8.0", "1.9.0", "1.9.1",
+		"2.0.0", "2.1.0", "2.2.0", "2.2.1", "2.3.0-rc.1", "2.3.0", "2.3.1-rc.1", "2.4.0-beta.1",
+	))
+
+	Convey("Test supported lines", t, func() {
+		lines := semver.SupportPolicy{Minors: 2, PreviousMajors: 1}.SupportedLines(versions)
+		var names []string
+		for _, l := range lines {
+			names = append(names, l.String())
+		}
+		So(names, ShouldResemble, []string{"1.9.x", "2.2.x", "2.3.x"})
+
+		So(semver.SupportPolicy{Minors: 1}.SupportedLines(versions), ShouldHaveLength, 1)
+		So(semver.SupportPolicy{PreviousMajors: 5}.SupportedLines(versions), ShouldHaveLength, 2)
+		So(semver.SupportPolicy{Minors: 2}.SupportedLines(nil), ShouldBeEmpty)
+	})
+
+	Convey("Test support evaluation", t, func() {
+		expected := map[string]struct {
+			status  semver.SupportStatus
+			upgrade string
+		}{
+			"0.9.0":        {semver.StatusEOL, ""},
+			"1.8.0":        {semver.StatusDeprecated, "1.9.1"},
+			"1.9.0":        {semver.StatusDeprecated, "1.9.1"},
+			"1.9.1":        {semver.StatusSupported, ""},
+			"2.0.0":        {semver.StatusDeprecated, "2.2.1"},
+			"2.1.0":        {semver.StatusDeprecated, "2.2.1"},
+			"2.2.0":        {semver.StatusDeprecated, "2.2.1"},
+			"2.2.1":        {semver.StatusSupported, ""},
+			"2.3.0-rc.1":   {semver.StatusDeprecated, "2.3.0"},
+			"2.3.0":        {semver.StatusSupported, ""},
+			"2.3.1-rc.1":   {semver.StatusEOL, ""},
+			"2.4.0-beta.1": {semver.StatusEOL, ""},
+		}
+
+		statuses := semver.SupportPolicy{Minors: 2, PreviousMajors: 1}.Evaluate(versions)
+		So(statuses, ShouldHaveLength, len(expected))
+		for i, s := range statuses {
+			if i > 0 {
+				So(statuses[i-1].Version.LT(s.Version), ShouldBeTrue)
+			}
+			e := expected[s.Version.String()]
+			So(s.Status, ShouldEqual, e.status)
+			if e.upgrade == "" {
+				So(s.Upgrade, ShouldBeNil)
+			} else {
+				So(s.Upgrade, ShouldResemble, semver.New(e.upgrade))
+			}
+		}
+	})
+
+	Convey("Test support status names", t, func() {
+		So(semver.StatusSupported.String(), ShouldEqual, "supported")
+		So(semver.StatusEOL.String(), ShouldEqual, "EOL")
+		So(semver.SupportStatus(7).String(), ShouldEqual, "SupportStatus(7)")
+	})
+}
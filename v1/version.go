@@ -153,6 +153,16 @@ func (v *Version) Set(s string) error {
 	return nil
 }
 
+// MarshalText implements the encoding.TextMarshaler interface.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (v *Version) UnmarshalText(text []byte) error {
+	return v.Set(string(text))
+}
+
 // IncrementMajor increments the major version while clearing both the pre-release and build metadata.
 func (v *Version) IncrementMajor() *Version {
 	return &Version{
@@ -184,6 +194,7 @@ func (v *Version) IncrementPatch() *Version {
 }
 
 // CompatibleUnder returns true if v is compatible under o and false otherwise.
+// See CompatibilityPolicy for checks that handle 0.x versions and pre-releases.
 func (v *Version) CompatibleUnder(o *Version) bool {
 	if v.Major != o.Major {
 		return false
@@ -17,6 +17,8 @@
 package semver_test
 
 import (
+	"encoding"
+	"encoding/xml"
 	"errors"
 	"testing"
 
@@ -249,3 +251,31 @@ func TestClone(t *testing.T) {
 		So(cloned, ShouldResemble, v)
 	})
 }
+
+func TestVersionText(t *testing.T) {
+	Convey("Test version text", t, func() {
+		var v semver.Version
+		var _ encoding.TextMarshaler = v
+		var _ encoding.TextUnmarshaler = &v
+
+		So(v.UnmarshalText([]byte("1.2")), ShouldNotBeNil)
+		So(v.UnmarshalText([]byte("1.2.3-rc.1+build.5")), ShouldBeNil)
+		text, err := v.MarshalText()
+		So(err, ShouldBeNil)
+		So(string(text), ShouldEqual, "1.2.3-rc.1+build.5")
+	})
+
+	Convey("Test version text in XML attributes", t, func() {
+		type release struct {
+			Version semver.Version `xml:"version,attr"`
+		}
+		data, err := xml.Marshal(release{Version: *semver.New("2.0.0-beta.1")})
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `<release version="2.0.0-beta.1"></release>`)
+
+		var r release
+		So(xml.Unmarshal(data, &r), ShouldBeNil)
+		So(r.Version.String(), ShouldEqual, "2.0.0-beta.1")
+		So(xml.Unmarshal([]byte(`<release version="2.0"></release>`), &r), ShouldNotBeNil)
+	})
+}